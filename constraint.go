@@ -0,0 +1,159 @@
+package env_wrapper
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Key involved in a violated constraint and the layer which supplied its value.
+type KeySource struct {
+	Key    string
+	Source Source
+}
+
+// Describes a violated constraint between several variables.
+type ConstraintError struct {
+	Msg  string
+	Keys []KeySource
+}
+
+func (e *ConstraintError) Error() string {
+	keys := make([]string, len(e.Keys))
+	for i, k := range e.Keys {
+		src := string(k.Source)
+		if k.Source == SourceNone {
+			src = "unset"
+		}
+		keys[i] = fmt.Sprintf("%s: %s", k.Key, src)
+	}
+	return fmt.Sprintf("env_wrapper: %s (%s)", e.Msg, strings.Join(keys, ", "))
+}
+
+// Condition of a conditional constraint.
+type Condition struct {
+	name string
+	desc string
+	test func(w *env_wrapper) bool
+}
+
+// Holds if the variable is a true boolean value.
+func IsTrue(name string) Condition {
+	return Condition{name, "is true", func(w *env_wrapper) bool {
+		return w.GetBoolDef(name, false)
+	}}
+}
+
+// Holds if the variable is set to a non empty value.
+func IsSet(name string) Condition {
+	return Condition{name, "is set", func(w *env_wrapper) bool {
+		return len(w.GetString(name)) > 0
+	}}
+}
+
+// Holds if the variable equals a value.
+func Equals(name, value string) Condition {
+	return Condition{name, fmt.Sprintf("is %q", value), func(w *env_wrapper) bool {
+		return w.GetString(name) == value
+	}}
+}
+
+// Checks several variables and returns nil if the constraint holds.
+type Constraint func(w *env_wrapper) *ConstraintError
+
+// Requires every variable if the condition holds.
+func RequiredIf(cond Condition, names ...string) Constraint {
+	return func(w *env_wrapper) *ConstraintError {
+		if !cond.test(w) {
+			return nil
+		}
+		for _, name := range names {
+			if _, src, _ := w.get(name); src == SourceNone {
+				return w.constraintError(fmt.Sprintf("%s required if %s %s", w.joinKeys(names), w.key(cond.name), cond.desc),
+					append([]string{cond.name}, names...))
+			}
+		}
+		return nil
+	}
+}
+
+// Requires exactly one of the variables.
+func ExactlyOneOf(names ...string) Constraint {
+	return func(w *env_wrapper) *ConstraintError {
+		if w.countSet(names) != 1 {
+			return w.constraintError(fmt.Sprintf("exactly one of %s required", w.joinKeys(names)), names)
+		}
+		return nil
+	}
+}
+
+// Allows at most one of the variables.
+func AtMostOneOf(names ...string) Constraint {
+	return func(w *env_wrapper) *ConstraintError {
+		if w.countSet(names) > 1 {
+			return w.constraintError(fmt.Sprintf("at most one of %s allowed", w.joinKeys(names)), names)
+		}
+		return nil
+	}
+}
+
+// Requires either every or none of the variables.
+func AllOrNone(names ...string) Constraint {
+	return func(w *env_wrapper) *ConstraintError {
+		if count := w.countSet(names); count > 0 && count < len(names) {
+			return w.constraintError(fmt.Sprintf("all or none of %s required", w.joinKeys(names)), names)
+		}
+		return nil
+	}
+}
+
+// Checks every constraint and returns the violations as a ValidationError.
+// Violations are recorded for Validate.
+func (w *env_wrapper) CheckConstraints(constraints ...Constraint) error {
+	var errs []*ConstraintError
+	for _, c := range constraints {
+		if err := c(w); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if len(errs) == 0 {
+		return nil
+	}
+	w.mu.Lock()
+	for _, err := range errs {
+		w.addConstraintViolation(err)
+	}
+	w.mu.Unlock()
+	return &ValidationError{Constraints: errs}
+}
+
+// Counts the variables with a non empty value.
+func (w *env_wrapper) countSet(names []string) int {
+	res := 0
+	for _, name := range names {
+		if _, src, _ := w.get(name); src != SourceNone {
+			res++
+		}
+	}
+	return res
+}
+
+// Creates a ConstraintError with the sources of the involved keys.
+func (w *env_wrapper) constraintError(msg string, names []string) *ConstraintError {
+	res := &ConstraintError{
+		Msg: msg,
+	}
+	for _, name := range names {
+		_, src, _ := w.get(name)
+		res.Keys = append(res.Keys, KeySource{w.key(name), src})
+	}
+	return res
+}
+
+// Joins fully qualified keys for messages.
+func (w *env_wrapper) joinKeys(names []string) string {
+	keys := make([]string, len(names))
+	for i, name := range names {
+		keys[i] = w.key(name)
+	}
+	return strings.Join(keys, ", ")
+}
@@ -0,0 +1,85 @@
+package env_wrapper
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestConstraints(t *testing.T) {
+	w := newTestWrapper(t,
+		map[string]string{"TLS_KEY": "key"},
+		map[string]string{
+			"TLS_ENABLED": "true",
+			"TLS_CERT":    "cert",
+			"DB_URL":      "postgres://",
+			"DB_HOST":     "db",
+			"MODE":        "prod",
+			"OFF":         "false",
+			"EMPTY":       "",
+		},
+	)
+	tests := []struct {
+		name       string
+		constraint Constraint
+		msg        string
+		keys       []KeySource
+	}{
+		{"required if holds", RequiredIf(IsTrue("TLS_ENABLED"), "TLS_CERT", "TLS_KEY"), "", nil},
+		{"required if violated", RequiredIf(IsTrue("tls_enabled"), "TLS_CERT", "TLS_CA"),
+			"TLS_CERT, TLS_CA required if TLS_ENABLED is true",
+			[]KeySource{{"TLS_ENABLED", "env"}, {"TLS_CERT", "env"}, {"TLS_CA", SourceNone}}},
+		{"required if false", RequiredIf(IsTrue("OFF"), "MISSING"), "", nil},
+		{"required if unset", RequiredIf(IsSet("EMPTY"), "MISSING"), "", nil},
+		{"required if equals", RequiredIf(Equals("MODE", "prod"), "MISSING"),
+			`MISSING required if MODE is "prod"`,
+			[]KeySource{{"MODE", "env"}, {"MISSING", SourceNone}}},
+		{"exactly one violated", ExactlyOneOf("DB_URL", "DB_HOST"),
+			"exactly one of DB_URL, DB_HOST required",
+			[]KeySource{{"DB_URL", "env"}, {"DB_HOST", "env"}}},
+		{"exactly one none", ExactlyOneOf("MISSING", "EMPTY"),
+			"exactly one of MISSING, EMPTY required",
+			[]KeySource{{"MISSING", SourceNone}, {"EMPTY", SourceNone}}},
+		{"exactly one holds", ExactlyOneOf("DB_URL", "MISSING"), "", nil},
+		{"at most one violated", AtMostOneOf("DB_URL", "DB_HOST", "MISSING"),
+			"at most one of DB_URL, DB_HOST, MISSING allowed",
+			[]KeySource{{"DB_URL", "env"}, {"DB_HOST", "env"}, {"MISSING", SourceNone}}},
+		{"at most one holds", AtMostOneOf("MISSING", "EMPTY"), "", nil},
+		{"all or none violated", AllOrNone("TLS_KEY", "MISSING"),
+			"all or none of TLS_KEY, MISSING required",
+			[]KeySource{{"TLS_KEY", SourceSecret}, {"MISSING", SourceNone}}},
+		{"all or none all", AllOrNone("TLS_KEY", "TLS_CERT"), "", nil},
+		{"all or none none", AllOrNone("MISSING", "EMPTY"), "", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := w.CheckConstraints(tt.constraint)
+			if len(tt.msg) == 0 {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			var cerr *ConstraintError
+			if !errors.As(err, &cerr) {
+				t.Fatalf("expected a ConstraintError, got %v", err)
+			}
+			if cerr.Msg != tt.msg || !reflect.DeepEqual(cerr.Keys, tt.keys) {
+				t.Errorf("got %q %v, want %q %v", cerr.Msg, cerr.Keys, tt.msg, tt.keys)
+			}
+		})
+	}
+
+	err := w.Validate()
+	var verr *ValidationError
+	if !errors.As(err, &verr) || len(verr.Constraints) != 6 {
+		t.Errorf("expected Validate to report every violated constraint, got %v", err)
+	}
+}
+
+func TestConstraintErrorMessage(t *testing.T) {
+	err := &ConstraintError{"at most one of A, B allowed", []KeySource{{"A", "env"}, {"B", SourceNone}}}
+	if got, want := err.Error(), "env_wrapper: at most one of A, B allowed (A: env, B: unset)"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,315 @@
+package env_wrapper
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// Value was read from a dotenv file.
+const SourceDotenv Source = "dotenv"
+
+// Describes a syntax error in a dotenv file.
+type DotenvError struct {
+	Line int
+	Msg  string
+}
+
+func (e *DotenvError) Error() string {
+	return fmt.Sprintf("env_wrapper: dotenv line %d: %s", e.Line, e.Msg)
+}
+
+type dotenvProvider struct {
+	path   string
+	values map[string]string
+}
+
+// Creates a provider which serves the variables of a dotenv file.
+// Use NewChain to place it before or after the secrets and the process environment.
+func NewDotenvProvider(path string) (*dotenvProvider, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	values, err := ParseDotenv(file)
+	if err != nil {
+		return nil, err
+	}
+	return &dotenvProvider{
+		path,
+		values,
+	}, nil
+}
+
+func (p *dotenvProvider) Name() string {
+	return string(SourceDotenv)
+}
+
+func (p *dotenvProvider) Lookup(key string) (string, bool) {
+	res, ok := p.values[key]
+	return res, ok
+}
+
+func (p *dotenvProvider) Location(key string) string {
+	return p.path
+}
+
+func (p *dotenvProvider) Keys() []string {
+	res := make([]string, 0, len(p.values))
+	for key := range p.values {
+		res = append(res, key)
+	}
+	return res
+}
+
+// Parses dotenv content into a map with upper case keys.
+// Supports comments, "export" prefixes, single and double quotes, escape sequences
+// in double quotes, multiline quoted values and ${VAR} or $VAR interpolation.
+// Single quoted values are taken literally.
+func ParseDotenv(r io.Reader) (map[string]string, error) {
+	bval, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	p := &dotenvParser{
+		src:    string(bval),
+		line:   1,
+		values: make(map[string]string),
+	}
+	if err := p.parse(); err != nil {
+		return nil, err
+	}
+	return p.values, nil
+}
+
+type dotenvParser struct {
+	src    string
+	pos    int
+	line   int
+	values map[string]string
+}
+
+func (p *dotenvParser) errorf(format string, args ...any) error {
+	return &DotenvError{
+		Line: p.line,
+		Msg:  fmt.Sprintf(format, args...),
+	}
+}
+
+func (p *dotenvParser) eof() bool {
+	return p.pos >= len(p.src)
+}
+
+func (p *dotenvParser) peek() byte {
+	return p.src[p.pos]
+}
+
+// Advances by one byte and counts lines.
+func (p *dotenvParser) next() byte {
+	c := p.src[p.pos]
+	p.pos++
+	if c == '\n' {
+		p.line++
+	}
+	return c
+}
+
+func (p *dotenvParser) skipBlanks() {
+	for !p.eof() && (p.peek() == ' ' || p.peek() == '\t') {
+		p.pos++
+	}
+}
+
+func (p *dotenvParser) skipLine() {
+	for !p.eof() && p.next() != '\n' {
+	}
+}
+
+func (p *dotenvParser) parse() error {
+	for {
+		for !p.eof() && strings.IndexByte(" \t\r\n", p.peek()) >= 0 {
+			p.next()
+		}
+		if p.eof() {
+			return nil
+		}
+		if p.peek() == '#' {
+			p.skipLine()
+			continue
+		}
+		key := p.readKey()
+		if key == "export" && !p.eof() && (p.peek() == ' ' || p.peek() == '\t') {
+			p.skipBlanks()
+			key = p.readKey()
+		}
+		if len(key) == 0 {
+			return p.errorf("invalid key")
+		}
+		p.skipBlanks()
+		if p.eof() || p.peek() != '=' {
+			return p.errorf("missing '=' after %s", key)
+		}
+		p.pos++
+		p.skipBlanks()
+		value, err := p.readValue()
+		if err != nil {
+			return err
+		}
+		p.values[strings.ToUpper(key)] = value
+	}
+}
+
+func (p *dotenvParser) readKey() string {
+	start := p.pos
+	for !p.eof() && isKeyChar(p.peek(), p.pos == start) {
+		p.pos++
+	}
+	return p.src[start:p.pos]
+}
+
+// Reports if c is allowed at a position of a variable name.
+func isKeyChar(c byte, first bool) bool {
+	switch {
+	case c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z'):
+		return true
+	case !first && ((c >= '0' && c <= '9') || c == '.'):
+		return true
+	}
+	return false
+}
+
+func (p *dotenvParser) readValue() (string, error) {
+	if p.eof() {
+		return "", nil
+	}
+	var res string
+	var err error
+	switch p.peek() {
+	case '\'':
+		res, err = p.readSingleQuoted()
+	case '"':
+		res, err = p.readDoubleQuoted()
+	default:
+		return p.readUnquoted()
+	}
+	if err != nil {
+		return "", err
+	}
+	p.skipBlanks()
+	if !p.eof() && p.peek() == '#' {
+		p.skipLine()
+	}
+	if !p.eof() && p.peek() == '\r' {
+		p.pos++
+	}
+	if !p.eof() && p.next() != '\n' {
+		return "", p.errorf("unexpected characters after quoted value")
+	}
+	return res, nil
+}
+
+func (p *dotenvParser) readSingleQuoted() (string, error) {
+	line := p.line
+	p.pos++
+	start := p.pos
+	for !p.eof() {
+		if p.peek() == '\'' {
+			res := p.src[start:p.pos]
+			p.pos++
+			return res, nil
+		}
+		p.next()
+	}
+	p.line = line
+	return "", p.errorf("unterminated single quoted value")
+}
+
+func (p *dotenvParser) readDoubleQuoted() (string, error) {
+	line := p.line
+	p.pos++
+	var sb strings.Builder
+	for !p.eof() {
+		c := p.next()
+		switch c {
+		case '"':
+			return sb.String(), nil
+		case '\\':
+			if p.eof() {
+				break
+			}
+			switch e := p.next(); e {
+			case 'n':
+				sb.WriteByte('\n')
+			case 'r':
+				sb.WriteByte('\r')
+			case 't':
+				sb.WriteByte('\t')
+			case '"', '\\', '$', '\'':
+				sb.WriteByte(e)
+			case '\n':
+			default:
+				sb.WriteByte('\\')
+				sb.WriteByte(e)
+			}
+		case '$':
+			if err := p.expand(&sb); err != nil {
+				return "", err
+			}
+		default:
+			sb.WriteByte(c)
+		}
+	}
+	p.line = line
+	return "", p.errorf("unterminated double quoted value")
+}
+
+func (p *dotenvParser) readUnquoted() (string, error) {
+	var sb strings.Builder
+	for !p.eof() && p.peek() != '\n' {
+		c := p.src[p.pos]
+		if c == '#' && (p.pos == 0 || p.src[p.pos-1] == ' ' || p.src[p.pos-1] == '\t') {
+			p.skipLine()
+			return strings.TrimSpace(sb.String()), nil
+		}
+		p.pos++
+		if c == '$' {
+			if err := p.expand(&sb); err != nil {
+				return "", err
+			}
+			continue
+		}
+		sb.WriteByte(c)
+	}
+	return strings.TrimSpace(sb.String()), nil
+}
+
+// Writes the value of a ${VAR} or $VAR reference following a '$'.
+// References are resolved with earlier values of the file and the process environment.
+func (p *dotenvParser) expand(sb *strings.Builder) error {
+	braced := !p.eof() && p.peek() == '{'
+	if braced {
+		p.pos++
+	}
+	name := p.readKey()
+	if braced {
+		if p.eof() || p.peek() != '}' {
+			return p.errorf("unterminated variable reference")
+		}
+		p.pos++
+	}
+	if len(name) == 0 {
+		if braced {
+			return p.errorf("empty variable reference")
+		}
+		sb.WriteByte('$')
+		return nil
+	}
+	if res, ok := p.values[strings.ToUpper(name)]; ok {
+		sb.WriteString(res)
+	} else {
+		sb.WriteString(os.Getenv(name))
+	}
+	return nil
+}
@@ -0,0 +1,89 @@
+package env_wrapper
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseDotenv(t *testing.T) {
+	t.Setenv("DOTENV_TEST_HOME", "/home/test")
+	tests := []struct {
+		name    string
+		content string
+		want    map[string]string
+		err     string
+	}{
+		{"empty", "", map[string]string{}, ""},
+		{"simple", "A=1\nb=2", map[string]string{"A": "1", "B": "2"}, ""},
+		{"blanks", "  A = 1  \n\n\tB=2\r\n", map[string]string{"A": "1", "B": "2"}, ""},
+		{"empty value", "A=\nB=", map[string]string{"A": "", "B": ""}, ""},
+		{"comments", "# comment\nA=1 # trailing\nB=a#b", map[string]string{"A": "1", "B": "a#b"}, ""},
+		{"export", "export A=1\nexport=2", map[string]string{"A": "1", "EXPORT": "2"}, ""},
+		{"dotted key", "a.b_1=x", map[string]string{"A.B_1": "x"}, ""},
+		{"single quoted", `A='x $B \n # y'`, map[string]string{"A": `x $B \n # y`}, ""},
+		{"double quoted", `A="x # y" # comment`, map[string]string{"A": "x # y"}, ""},
+		{"escapes", `A="a\nb\tc\"d\\e\$f\'g\qh"`, map[string]string{"A": "a\nb\tc\"d\\e$f'g\\qh"}, ""},
+		{"line continuation", "A=\"a\\\nb\"", map[string]string{"A": "ab"}, ""},
+		{"multiline", "A=\"line1\nline2\"\nB='x\ny'", map[string]string{"A": "line1\nline2", "B": "x\ny"}, ""},
+		{"braced reference", "A=1\nB=${A}2", map[string]string{"A": "1", "B": "12"}, ""},
+		{"plain reference", "A=1\nB=\"$A-x\"", map[string]string{"A": "1", "B": "1-x"}, ""},
+		{"case insensitive reference", "a=1\nB=${a}", map[string]string{"A": "1", "B": "1"}, ""},
+		{"process reference", "A=${DOTENV_TEST_HOME}/x", map[string]string{"A": "/home/test/x"}, ""},
+		{"unknown reference", "A=x${DOTENV_TEST_MISSING}y", map[string]string{"A": "xy"}, ""},
+		{"literal dollar", "A=5$\nB=$ x", map[string]string{"A": "5$", "B": "$ x"}, ""},
+		{"escaped dollar", `A="\${B}"`, map[string]string{"A": "${B}"}, ""},
+		{"override", "A=1\nA=2", map[string]string{"A": "2"}, ""},
+		{"missing equals", "A 1", nil, "dotenv line 1: missing '=' after A"},
+		{"invalid key", "1A=1", nil, "dotenv line 1: invalid key"},
+		{"unterminated single", "A=1\nB='x\ny", nil, "dotenv line 2: unterminated single quoted value"},
+		{"unterminated double", "A=\"x", nil, "dotenv line 1: unterminated double quoted value"},
+		{"unterminated reference", "A=${B", nil, "dotenv line 1: unterminated variable reference"},
+		{"empty reference", "A=${}", nil, "dotenv line 1: empty variable reference"},
+		{"after quote", "A=\"x\" y", nil, "dotenv line 1: unexpected characters after quoted value"},
+		{"line numbers", "A=1\n\n# c\nB", nil, "dotenv line 4: missing '=' after B"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseDotenv(strings.NewReader(tt.content))
+			if len(tt.err) > 0 {
+				if err == nil || err.Error() != "env_wrapper: "+tt.err {
+					t.Fatalf("expected error %q, got %v", tt.err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestDotenvProvider(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".env")
+	if err := os.WriteFile(path, []byte("DB_HOST=db\nEMPTY=\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	p, err := NewDotenvProvider(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w := NewChain(p)
+	if got := w.GetString("db_host"); got != "db" {
+		t.Errorf("expected %q, got %q", "db", got)
+	}
+	if _, src := w.LookupSource("EMPTY"); src != SourceDotenv {
+		t.Errorf("expected an empty but present value, got %q", src)
+	}
+	if e := w.Explain("DB_HOST"); e.Location != path {
+		t.Errorf("expected location %q, got %q", path, e.Location)
+	}
+	if _, err := NewDotenvProvider(filepath.Join(t.TempDir(), "missing")); err == nil {
+		t.Error("expected an error for a missing file")
+	}
+}
@@ -1,47 +1,77 @@
 // Package env_wrapper provides simplified access to environment variables and docker secrets.
 // If a secret is present the environment variable will be ignored.
-// Every secret needs an "ENV_" prefix.
+// By default every secret needs an "ENV_" prefix.
+// Custom sources and precedences can be set up with NewChain and the Provider interface.
 package env_wrapper
 
 import (
-	"io/ioutil"
-	"os"
-	"strconv"
 	"strings"
+	"sync"
 )
 
 type env_wrapper struct {
-	envSecrets map[string]string
+	*core
+	prefix string
+}
+
+// State shared by a wrapper and its sub wrappers.
+type core struct {
+	providers            []Provider
+	mu                   sync.Mutex
+	violations           []*ValueError
+	constraintViolations []*ConstraintError
+	accessed             map[string]*access
+	accessOrder          []string
+	recorder             *Recorder
+	expansion            bool
+	resolving            bool
+	execAllowed          map[string]bool
+	files                *fileEnvProvider
 }
 
 // Creates a new EnvWrapper with the default secret directory.
-func Default() *env_wrapper {
-	return New("/run/secrets")
+func Default(opts ...Option) *env_wrapper {
+	return New("/run/secrets", opts...)
 }
 
 // Creates a new EnvWrapper with a custom secret directory.
-func New(secretsDir string) *env_wrapper {
-	res := &env_wrapper{
-		make(map[string]string),
+// Variables which are neither a secret nor set fall back to the file their "_FILE" variable points to.
+func New(secretsDir string, opts ...Option) *env_wrapper {
+	return NewChain(NewSecretsProvider(secretsDir, opts...), NewEnvProvider(), NewFileEnvProvider(opts...))
+}
+
+// Creates a new EnvWrapper like New.
+// Returns a ScanError listing every secret which couldn't be read.
+// With the Strict option a missing secret directory is an error as well.
+func NewE(secretsDir string, opts ...Option) (*env_wrapper, error) {
+	secrets, err := NewSecretsProviderE(secretsDir, opts...)
+	if err != nil {
+		return nil, err
 	}
-	path := strings.TrimRight(strings.TrimSpace(secretsDir), "\\//")
-	if _, ferr := os.Stat(path); !os.IsNotExist(ferr) {
-
-		files, ferr := ioutil.ReadDir(path)
-		if ferr == nil {
-			for _, file := range files {
-				if strings.HasPrefix(file.Name(), "ENV_") && !file.IsDir() && file.Size() > 0 {
-					bval, eerr := ioutil.ReadFile(path + "/" + file.Name())
-					if eerr == nil {
-						sval := strings.TrimSpace(string(bval))
-						keyname := strings.TrimPrefix(strings.ToUpper(file.Name()), "ENV_")
-						res.envSecrets[keyname] = sval
-					}
-				}
-			}
+	return NewChain(secrets, NewEnvProvider(), NewFileEnvProvider(opts...)), nil
+}
+
+// Creates a new EnvWrapper which reads a mounted kubernetes secret volume.
+func NewKubernetes(secretsDir string, opts ...Option) *env_wrapper {
+	return New(secretsDir, append([]Option{KubernetesLayout()}, opts...)...)
+}
+
+// Creates a new EnvWrapper which asks the providers in the given order.
+// File references of EnableResolvers use the limits of the first "_FILE" provider.
+func NewChain(providers ...Provider) *env_wrapper {
+	files := NewFileEnvProvider()
+	for _, p := range providers {
+		if fp, ok := p.(*fileEnvProvider); ok {
+			files = fp
+			break
 		}
 	}
-	return res
+	return &env_wrapper{
+		core: &core{
+			providers: providers,
+			files:     files,
+		},
+	}
 }
 
 // Gets a string value or returns an empty string if the variable doesn't exist.
@@ -51,19 +81,92 @@ func (w *env_wrapper) GetString(name string) string {
 
 // Gets a string value or returns a default value if the string is empty.
 func (w *env_wrapper) GetStringDef(name, defval string) string {
-	res := defval
-	upname := strings.ToUpper(name)
-	senvval := w.envSecrets[upname]
-	if len(senvval) > 0 {
-		res = senvval
-	} else {
-		envval := strings.TrimSpace(os.Getenv(upname))
-		if len(envval) > 0 {
-			res = envval
+	return Get(w, name, defval)
+}
+
+// Gets a string value or returns an error if the variable doesn't exist.
+func (w *env_wrapper) GetStringE(name string) (string, error) {
+	return GetE[string](w, name)
+}
+
+// Gets the first non empty value and the layer which supplied it.
+// Returns a ValueError if a provider fails to read a present value.
+func (w *env_wrapper) get(name string) (string, Source, error) {
+	return w.find(name, false)
+}
+
+// Gets the first present or non empty value and the layer which supplied it.
+func (w *env_wrapper) find(name string, present bool) (string, Source, error) {
+	upname := w.key(name)
+	w.track(upname)
+	for _, p := range w.providers {
+		strval, ok, err := lookupE(p, upname)
+		if err != nil {
+			src := Source(p.Name())
+			return "", src, w.valueError(name, resolved{"", src, w.sensitive(src)}, err)
+		}
+		if (present && ok) || len(strval) > 0 {
+			return strval, Source(p.Name()), nil
 		}
 	}
+	return "", SourceNone, nil
+}
 
-	return res
+// Looks a key up and returns the read error of a CheckedProvider.
+func lookupE(p Provider, key string) (string, bool, error) {
+	if cp, ok := p.(CheckedProvider); ok {
+		return cp.LookupE(key)
+	}
+	strval, ok := p.Lookup(key)
+	return strval, ok, nil
+}
+
+// Value of a variable after expansion and resolving.
+type resolved struct {
+	value  string
+	source Source
+	// Value was read from or built with a sensitive layer.
+	sensitive bool
+}
+
+// Gets the first non empty value, expands its references and resolves its scheme if enabled.
+// Returns a ValueError if the value can't be read, expanded or resolved.
+func (w *env_wrapper) resolve(name string) (resolved, error) {
+	return w.resolveFind(name, false)
+}
+
+// Gets the first present value like resolve.
+// Empty secrets and empty environment variables count as present.
+func (w *env_wrapper) resolvePresent(name string) (resolved, error) {
+	return w.resolveFind(name, true)
+}
+
+func (w *env_wrapper) resolveFind(name string, present bool) (resolved, error) {
+	strval, src, err := w.find(name, present)
+	res := resolved{strval, src, w.sensitive(src) || w.readAsSecret(w.key(name))}
+	if err != nil || src == SourceNone {
+		return res, err
+	}
+	e := w.expander(w.key(name))
+	strval, err = e.value(res.value, src)
+	res.sensitive = res.sensitive || e.sensitive
+	if err != nil {
+		return res, w.valueError(name, res, err)
+	}
+	res.value = strval
+	return res, nil
+}
+
+// Reports if values of a layer must never be printed.
+func (w *env_wrapper) sensitive(src Source) bool {
+	for _, p := range w.providers {
+		if Source(p.Name()) == src {
+			if sp, ok := p.(SensitiveProvider); ok && sp.Sensitive() {
+				return true
+			}
+		}
+	}
+	return false
 }
 
 // Gets a boolean value or returns false if the variable doesn't exist.
@@ -73,14 +176,22 @@ func (w *env_wrapper) GetBool(name string) bool {
 
 // Gets a boolean value or returns a default value if variable doesn't exist.
 func (w *env_wrapper) GetBoolDef(name string, defval bool) bool {
-	strval := w.GetString(name)
-	if len(strval) > 0 {
-		res, err := strconv.ParseBool(strval)
-		if err == nil {
-			return res
-		}
+	res, err := w.GetBoolDefE(name, defval)
+	if err != nil {
+		return defval
 	}
-	return defval
+	return res
+}
+
+// Gets a boolean value or returns an error if the variable doesn't exist or can't be parsed.
+func (w *env_wrapper) GetBoolE(name string) (bool, error) {
+	return GetE[bool](w, name)
+}
+
+// Gets a boolean value or returns a default value if variable doesn't exist.
+// Returns an error if the value can't be parsed.
+func (w *env_wrapper) GetBoolDefE(name string, defval bool) (bool, error) {
+	return GetDefE(w, name, defval)
 }
 
 // Gets a integer value or returns 0 if the variable doesn't exist.
@@ -90,25 +201,51 @@ func (w *env_wrapper) GetInt(name string) int {
 
 // Gets a integer value or returns a default value if variable doesn't exist.
 func (w *env_wrapper) GetIntDef(name string, defval int) int {
-	strval := w.GetString(name)
-	if len(strval) > 0 {
-		res, err := strconv.Atoi(strval)
-		if err == nil {
-			return res
-		}
+	res, err := w.GetIntDefE(name, defval)
+	if err != nil {
+		return defval
 	}
-	return defval
+	return res
+}
+
+// Gets a integer value or returns an error if the variable doesn't exist or can't be parsed.
+func (w *env_wrapper) GetIntE(name string) (int, error) {
+	return GetE[int](w, name)
+}
+
+// Gets a integer value or returns a default value if variable doesn't exist.
+// Returns an error if the value can't be parsed.
+func (w *env_wrapper) GetIntDefE(name string, defval int) (int, error) {
+	return GetDefE(w, name, defval)
 }
 
 // Gets a string array by splitting the value with the whitespace character.
+// Elements containing whitespace can be quoted or escaped like in SplitList.
 func (w *env_wrapper) GetStringArray(name string) []string {
 	return w.GetStringArraySep(name, " ")
 }
 
-// Gets a string array by splitting the value with a seperator.
+// Gets a string array by splitting the value with a seperator like SplitList.
+// Double quotes and backslashes are interpreted, so values containing them literally have to escape them.
+// Malformed values, like an unterminated quote, are split at every seperator as before.
 func (w *env_wrapper) GetStringArraySep(name, seperator string) []string {
+	res, _ := w.resolve(name)
+	w.record(name, "[]string", nil, res.source)
+	return splitArray(res.value, seperator)
+}
+
+// Splits a value with SplitList or falls back to splitClean if the value is malformed.
+func splitArray(strval, seperator string) []string {
+	res, err := SplitList(strval, seperator)
+	if err != nil {
+		return splitClean(strval, seperator)
+	}
+	return res
+}
+
+// Splits a value with a seperator and drops empty parts.
+func splitClean(strval, seperator string) []string {
 	res := []string{}
-	strval := w.GetString(name)
 	if len(strval) > 0 {
 		strparts := strings.Split(strval, seperator)
 		for _, s := range strparts {
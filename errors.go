@@ -0,0 +1,136 @@
+package env_wrapper
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Placeholder which replaces secret values in messages.
+const redacted = "[REDACTED]"
+
+// Layer which supplied a value.
+type Source string
+
+const (
+	// No layer supplied a value.
+	SourceNone Source = ""
+	// Value was read from the secret directory.
+	SourceSecret Source = "secret"
+	// Value was read from the process environment.
+	SourceEnv Source = "env"
+	// Value is the default supplied by the caller.
+	SourceDefault Source = "default"
+)
+
+var (
+	// Returned if a required variable doesn't exist.
+	ErrNotSet = errors.New("variable is not set")
+	// Returned if a referenced file exceeds the size limit.
+	ErrFileTooLarge = errors.New("file exceeds size limit")
+	// Returned if a referenced file is located outside of the allowed directory.
+	ErrOutsideDir = errors.New("file is outside of the allowed directory")
+)
+
+// Describes a variable which couldn't be used.
+// Value is redacted if it was read from a sensitive layer.
+type ValueError struct {
+	Key    string
+	Value  string
+	Source Source
+	Err    error
+}
+
+// Creates a ValueError and redacts sensitive values, like those of sensitive layers or built from them.
+// strconv errors are unwrapped as they repeat the raw value,
+// other errors repeating a sensitive value are rewritten.
+func (w *env_wrapper) valueError(name string, r resolved, err error) *ValueError {
+	err = numErrCause(err)
+	value := r.value
+	if r.sensitive {
+		err = redactError(err, value)
+		value = redacted
+	}
+	return &ValueError{
+		Key:    w.key(name),
+		Value:  value,
+		Source: r.source,
+		Err:    err,
+	}
+}
+
+// Rewrites an error which repeats a sensitive value.
+func redactError(err error, value string) error {
+	if len(value) > 0 && strings.Contains(err.Error(), value) {
+		return errors.New(strings.ReplaceAll(err.Error(), value, redacted))
+	}
+	return err
+}
+
+// Unwraps strconv errors as they repeat the raw value.
+func numErrCause(err error) error {
+	if nerr, ok := err.(*strconv.NumError); ok {
+		return nerr.Err
+	}
+	return err
+}
+
+func (e *ValueError) Error() string {
+	if e.Source == SourceNone {
+		return fmt.Sprintf("env_wrapper: %s: %v", e.Key, e.Err)
+	}
+	return fmt.Sprintf("env_wrapper: %s=%q (%s): %v", e.Key, e.Value, e.Source, e.Err)
+}
+
+func (e *ValueError) Unwrap() error {
+	return e.Err
+}
+
+// Lists every entry of a secret directory which couldn't be read.
+type ScanError struct {
+	Dir  string
+	Errs []error
+}
+
+func (e *ScanError) Error() string {
+	msgs := make([]string, len(e.Errs))
+	for i, err := range e.Errs {
+		msgs[i] = err.Error()
+	}
+	return fmt.Sprintf("env_wrapper: reading secrets from %s failed: %s", e.Dir, strings.Join(msgs, "; "))
+}
+
+func (e *ScanError) Unwrap() []error {
+	return e.Errs
+}
+
+// Reports if any listed error matches target.
+// errors.Is only unwraps []error since Go 1.20.
+func (e *ScanError) Is(target error) bool {
+	return anyIs(e.Errs, target)
+}
+
+// Finds the first listed error which matches target.
+// errors.As only unwraps []error since Go 1.20.
+func (e *ScanError) As(target any) bool {
+	return anyAs(e.Errs, target)
+}
+
+func anyIs(errs []error, target error) bool {
+	for _, err := range errs {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+func anyAs(errs []error, target any) bool {
+	for _, err := range errs {
+		if errors.As(err, target) {
+			return true
+		}
+	}
+	return false
+}
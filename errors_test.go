@@ -0,0 +1,37 @@
+package env_wrapper
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"testing"
+)
+
+func TestMultiErrorMatching(t *testing.T) {
+	perr := &os.PathError{Op: "open", Path: "/run/secrets/ENV_X", Err: fs.ErrPermission}
+	serr := &ScanError{"/run/secrets", []error{errors.New("other"), perr}}
+	verr := &ValidationError{
+		Errs:        []*ValueError{{Key: "PORT", Err: ErrNotSet}},
+		Constraints: []*ConstraintError{{Msg: "exactly one of A, B required"}},
+	}
+
+	// The methods are called directly as errors.Is and errors.As ignore Unwrap() []error before Go 1.20.
+	if !serr.Is(fs.ErrPermission) || serr.Is(fs.ErrNotExist) || !errors.Is(serr, fs.ErrPermission) {
+		t.Error("expected ScanError to match the listed error only")
+	}
+	var target *os.PathError
+	if !serr.As(&target) || target != perr {
+		t.Errorf("expected ScanError to find the path error, got %v", target)
+	}
+	if !verr.Is(ErrNotSet) || verr.Is(ErrFileTooLarge) || !errors.Is(verr, ErrNotSet) {
+		t.Error("expected ValidationError to match the listed error only")
+	}
+	var cerr *ConstraintError
+	if !verr.As(&cerr) || cerr != verr.Constraints[0] {
+		t.Errorf("expected ValidationError to find the constraint error, got %v", cerr)
+	}
+	var vaerr *ValueError
+	if !verr.As(&vaerr) || vaerr.Key != "PORT" {
+		t.Errorf("expected ValidationError to find the value error, got %v", vaerr)
+	}
+}
@@ -0,0 +1,165 @@
+package env_wrapper
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Returned if variable references form a cycle.
+var ErrReferenceCycle = errors.New("reference cycle")
+
+// Turns on ${VAR} interpolation for every getter, Lookup, Check and Unmarshal.
+// ${VAR:-default} falls back to a default and ${VAR:?message} fails if VAR isn't set, "$$" is a literal '$'.
+// References are fully qualified keys, resolved by every provider and expanded recursively.
+// Values of sensitive layers like secrets are substituted literally and never expanded themselves.
+// Values built from sensitive layers are redacted in errors and explanations.
+func (w *env_wrapper) EnableExpansion() *env_wrapper {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.expansion = true
+	return w
+}
+
+// Expands the references of a single value and resolves its scheme.
+type expander struct {
+	root      *env_wrapper
+	expansion bool
+	resolving bool
+	stack     []string
+	refs      []KeySource
+	sensitive bool
+}
+
+// Creates an expander for the value of a fully qualified key with the enabled features of the wrapper.
+func (w *env_wrapper) expander(upname string) *expander {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	return &expander{
+		root:      &env_wrapper{core: w.core},
+		expansion: w.expansion,
+		resolving: w.resolving,
+		stack:     []string{upname},
+	}
+}
+
+// Expands and resolves a value of a layer if enabled.
+// Values of sensitive layers are never expanded as generated passwords often contain '$',
+// they are only substituted into other values. Resolved values are marked sensitive.
+func (e *expander) value(strval string, src Source) (string, error) {
+	res := strval
+	if e.expansion && !e.root.sensitive(src) {
+		var err error
+		if res, err = e.expand(res); err != nil {
+			return "", err
+		}
+	}
+	if !e.resolving {
+		return res, nil
+	}
+	res, resolved, err := e.root.resolveRef(res)
+	e.sensitive = e.sensitive || resolved
+	return res, err
+}
+
+func (e *expander) expand(strval string) (string, error) {
+	var sb strings.Builder
+	for i := 0; i < len(strval); i++ {
+		c := strval[i]
+		if c != '$' || i+1 == len(strval) || (strval[i+1] != '$' && strval[i+1] != '{') {
+			sb.WriteByte(c)
+			continue
+		}
+		if strval[i+1] == '$' {
+			sb.WriteByte('$')
+			i++
+			continue
+		}
+		end := closingBrace(strval, i+2)
+		if end < 0 {
+			return "", errors.New("unterminated variable reference")
+		}
+		res, err := e.reference(strval[i+2 : end])
+		if err != nil {
+			return "", err
+		}
+		sb.WriteString(res)
+		i = end
+	}
+	return sb.String(), nil
+}
+
+// Finds the brace closing a reference, nested references are skipped.
+func closingBrace(strval string, start int) int {
+	depth := 0
+	for i := start; i < len(strval); i++ {
+		switch {
+		case strval[i] == '$' && i+1 < len(strval) && strval[i+1] == '{':
+			depth++
+			i++
+		case strval[i] == '}':
+			if depth == 0 {
+				return i
+			}
+			depth--
+		}
+	}
+	return -1
+}
+
+// Resolves the content of a ${...} reference.
+func (e *expander) reference(ref string) (string, error) {
+	name, op, arg := ref, "", ""
+	if i := strings.IndexByte(ref, ':'); i >= 0 {
+		if i+1 == len(ref) || (ref[i+1] != '-' && ref[i+1] != '?') {
+			return "", fmt.Errorf("invalid variable reference ${%s}", ref)
+		}
+		name, op, arg = ref[:i], ref[i+1:i+2], ref[i+2:]
+	}
+	if len(name) == 0 {
+		return "", errors.New("empty variable reference")
+	}
+	key := strings.ToUpper(name)
+	for i, k := range e.stack {
+		if k == key {
+			path := append(append([]string{}, e.stack[i:]...), key)
+			return "", fmt.Errorf("%w: %s", ErrReferenceCycle, strings.Join(path, " -> "))
+		}
+	}
+	strval, src, err := e.root.get(key)
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", key, errors.Unwrap(err))
+	}
+	e.addRef(key, src)
+	if src == SourceNone {
+		switch op {
+		case "-":
+			return e.expand(arg)
+		case "?":
+			if len(arg) == 0 {
+				arg = ErrNotSet.Error()
+			}
+			return "", fmt.Errorf("%s: %s", key, arg)
+		}
+		return "", nil
+	}
+	if e.root.sensitive(src) {
+		e.sensitive = true
+	}
+	e.stack = append(e.stack, key)
+	res, err := e.value(strval, src)
+	e.stack = e.stack[:len(e.stack)-1]
+	return res, err
+}
+
+// Remembers a referenced key once.
+func (e *expander) addRef(key string, src Source) {
+	for _, ref := range e.refs {
+		if ref.Key == key {
+			return
+		}
+	}
+	e.refs = append(e.refs, KeySource{key, src})
+}
@@ -0,0 +1,195 @@
+package env_wrapper
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// Creates a wrapper with a secret directory and a map layer as environment.
+func newTestWrapper(t *testing.T, secrets, env map[string]string) *env_wrapper {
+	t.Helper()
+	dir := t.TempDir()
+	for name, value := range secrets {
+		if err := os.WriteFile(filepath.Join(dir, "ENV_"+name), []byte(value), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return NewChain(NewSecretsProvider(dir), NewMapProvider("env", env))
+}
+
+func TestExpand(t *testing.T) {
+	env := map[string]string{
+		"USER":    "bob",
+		"HOST":    "db",
+		"EMPTY":   "",
+		"NESTED":  "${USER}@${HOST}",
+		"A":       "${B}",
+		"B":       "${C}",
+		"C":       "${a}",
+		"SELF":    "${SELF}",
+		"LOWER":   "${user}",
+		"DOLLARS": "$$USER",
+	}
+	tests := []struct {
+		value string
+		want  string
+		err   string
+	}{
+		{"plain", "plain", ""},
+		{"${USER}", "bob", ""},
+		{"x-${USER}-y", "x-bob-y", ""},
+		{"${LOWER}", "bob", ""},
+		{"${NESTED}", "bob@db", ""},
+		{"${MISSING}", "", ""},
+		{"${EMPTY:-def}", "def", ""},
+		{"${MISSING:-def}", "def", ""},
+		{"${USER:-def}", "bob", ""},
+		{"${MISSING:-${USER}}", "bob", ""},
+		{"${MISSING:-}", "", ""},
+		{"${USER:?required}", "bob", ""},
+		{"${MISSING:?set it}", "", "MISSING: set it"},
+		{"${MISSING:?}", "", "MISSING: variable is not set"},
+		{"$$", "$", ""},
+		{"$${USER}", "${USER}", ""},
+		{"${DOLLARS}", "$USER", ""},
+		{"$USER", "$USER", ""},
+		{"cost $5", "cost $5", ""},
+		{"end$", "end$", ""},
+		{"${USER", "", "unterminated variable reference"},
+		{"${}", "", "empty variable reference"},
+		{"${USER:x}", "", "invalid variable reference ${USER:x}"},
+		{"${A}", "", "reference cycle: A -> B -> C -> A"},
+		{"${SELF}", "", "reference cycle: SELF -> SELF"},
+	}
+	w := newTestWrapper(t, nil, env).EnableExpansion()
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, err := w.expander("VALUE").value(tt.value, SourceEnv)
+			if len(tt.err) > 0 {
+				if err == nil || err.Error() != tt.err {
+					t.Fatalf("expected error %q, got %v", tt.err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Fatalf("expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestExpandDisabled(t *testing.T) {
+	w := newTestWrapper(t, nil, map[string]string{"USER": "bob", "URL": "${USER}"})
+	if got := w.GetString("URL"); got != "${USER}" {
+		t.Fatalf("expected the raw value, got %q", got)
+	}
+	w.EnableExpansion()
+	if got := w.GetString("URL"); got != "bob" {
+		t.Fatalf("expected %q, got %q", "bob", got)
+	}
+}
+
+func TestExpandGetters(t *testing.T) {
+	w := newTestWrapper(t, nil, map[string]string{
+		"PRIMARY": "a",
+		"HOSTS":   "${PRIMARY} b",
+		"LIST":    "${PRIMARY},b",
+		"MAP":     "k=${PRIMARY}",
+		"JSON":    `{"host":"${PRIMARY}"}`,
+		"CYCLE":   "${CYCLE}",
+	}).EnableExpansion()
+
+	if got := w.GetStringArray("HOSTS"); strings.Join(got, "|") != "a|b" {
+		t.Errorf("GetStringArray: got %q", got)
+	}
+	if got, ok := w.LookupString("HOSTS"); !ok || got != "a b" {
+		t.Errorf("LookupString: got %q, %v", got, ok)
+	}
+	if got := w.GetStringList("LIST"); strings.Join(got, "|") != "a|b" {
+		t.Errorf("GetStringList: got %q", got)
+	}
+	if got := w.GetStringMap("MAP"); got["k"] != "a" {
+		t.Errorf("GetStringMap: got %q", got)
+	}
+	var doc struct {
+		Host string `json:"host"`
+	}
+	if err := w.GetJSON("JSON", &doc); err != nil || doc.Host != "a" {
+		t.Errorf("GetJSON: got %q, %v", doc.Host, err)
+	}
+	if got := w.GetStringMapByPrefix("PRI"); got["MARY"] != "a" {
+		t.Errorf("GetStringMapByPrefix: got %q", got)
+	}
+	if _, err := w.GetStringE("CYCLE"); !errors.Is(err, ErrReferenceCycle) {
+		t.Errorf("expected ErrReferenceCycle, got %v", err)
+	}
+	if got := w.GetStringDef("CYCLE", "def"); got != "def" {
+		t.Errorf("expected the default on errors, got %q", got)
+	}
+}
+
+func TestExpandRedaction(t *testing.T) {
+	w := newTestWrapper(t, map[string]string{"PASSWORD": "hunter2"}, map[string]string{
+		"URL":  "postgres://bob:${PASSWORD}@db",
+		"PORT": "${PASSWORD}",
+		"USER": "bob",
+		"NAME": "${USER}x",
+	}).EnableExpansion()
+
+	if got := w.GetString("URL"); got != "postgres://bob:hunter2@db" {
+		t.Fatalf("expected the expanded value, got %q", got)
+	}
+	_, err := w.GetIntE("PORT")
+	if err == nil || strings.Contains(err.Error(), "hunter2") || !strings.Contains(err.Error(), redacted) {
+		t.Errorf("expected a redacted error, got %v", err)
+	}
+	_, err = w.GetIntE("NAME")
+	if err == nil || !strings.Contains(err.Error(), "bobx") {
+		t.Errorf("expected the plain value in the error, got %v", err)
+	}
+	e := w.Explain("URL")
+	if e.Value != redacted || e.Source != SourceEnv || strings.Contains(e.String(), "hunter2") {
+		t.Errorf("expected a redacted explanation, got %s", e)
+	}
+	if len(e.Refs) != 1 || e.Refs[0] != (KeySource{"PASSWORD", SourceSecret}) {
+		t.Errorf("expected the secret reference, got %v", e.Refs)
+	}
+}
+
+func TestExpandSkipsSensitiveLayers(t *testing.T) {
+	secrets := map[string]string{
+		"BROKEN": "pa$$w${rd",
+		"DOUBLE": "pa$$word",
+		"REF":    "${USER}",
+	}
+	w := newTestWrapper(t, secrets, map[string]string{
+		"USER": "bob",
+		"URL":  "u:${BROKEN}@h",
+		"URL2": "u:${DOUBLE}@h",
+	}).EnableExpansion()
+
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"BROKEN", "pa$$w${rd"},
+		{"DOUBLE", "pa$$word"},
+		{"REF", "${USER}"},
+		{"URL", "u:pa$$w${rd@h"},
+		{"URL2", "u:pa$$word@h"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := w.GetStringE(tt.name)
+			if err != nil || got != tt.want {
+				t.Fatalf("expected %q, got %q, %v", tt.want, got, err)
+			}
+		})
+	}
+}
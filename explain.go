@@ -0,0 +1,198 @@
+package env_wrapper
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Value of a key in a single layer.
+type Layer struct {
+	Source   Source
+	Location string
+	Value    string
+}
+
+// Describes how the value of a key was resolved.
+// Shadowed lists the other layers which contain the key.
+// Refs lists the keys referenced by the value if expansion is enabled.
+type Explanation struct {
+	Key      string
+	Value    string
+	Source   Source
+	Location string
+	Shadowed []Layer
+	Refs     []KeySource
+}
+
+// Renders the explanation as a single line.
+func (e Explanation) String() string {
+	var sb strings.Builder
+	if e.Source == SourceNone {
+		fmt.Fprintf(&sb, "%s is unset", e.Key)
+	} else {
+		fmt.Fprintf(&sb, "%s=%q (%s", e.Key, e.Value, e.Source)
+		if len(e.Location) > 0 {
+			fmt.Fprintf(&sb, ": %s", e.Location)
+		}
+		sb.WriteString(")")
+	}
+	if len(e.Refs) > 0 {
+		refs := make([]string, len(e.Refs))
+		for i, ref := range e.Refs {
+			src := string(ref.Source)
+			if ref.Source == SourceNone {
+				src = "unset"
+			}
+			refs[i] = fmt.Sprintf("%s (%s)", ref.Key, src)
+		}
+		fmt.Fprintf(&sb, ", expands %s", strings.Join(refs, ", "))
+	}
+	for _, l := range e.Shadowed {
+		fmt.Fprintf(&sb, ", shadows %q (%s", l.Value, l.Source)
+		if len(l.Location) > 0 {
+			fmt.Fprintf(&sb, ": %s", l.Location)
+		}
+		sb.WriteString(")")
+	}
+	return sb.String()
+}
+
+// Records how a key was accessed by the getters.
+type access struct {
+	defval string
+	hasDef bool
+	// Key was read as Secret.
+	secret bool
+}
+
+// Remembers an accessed key in the order of the first access.
+func (w *env_wrapper) track(upname string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.accessed == nil {
+		w.accessed = make(map[string]*access)
+	}
+	if _, ok := w.accessed[upname]; !ok {
+		w.accessed[upname] = &access{}
+		w.accessOrder = append(w.accessOrder, upname)
+	}
+}
+
+// Remembers the default supplied for a key.
+func (w *env_wrapper) trackDefault(name, defval string) {
+	upname := w.key(name)
+	w.track(upname)
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.accessed[upname].defval = defval
+	w.accessed[upname].hasDef = true
+}
+
+// Remembers a key read as Secret, its values are redacted like those of sensitive layers.
+func (w *env_wrapper) trackSecret(name string) {
+	upname := w.key(name)
+	w.track(upname)
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.accessed[upname].secret = true
+}
+
+// Reports if a fully qualified key was read as Secret.
+func (w *env_wrapper) readAsSecret(upname string) bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	a, ok := w.accessed[upname]
+	return ok && a.secret
+}
+
+// Explains which layer supplies the value of a variable and which layers it shadows.
+// If any layer containing the key or a referenced key is sensitive or the key was read as Secret every value is redacted.
+// If no layer supplies a value the last default passed to a getter is reported.
+func (w *env_wrapper) Explain(name string) Explanation {
+	return w.explain(w.key(name))
+}
+
+// Explains a fully qualified key.
+func (w *env_wrapper) explain(upname string) Explanation {
+	res := Explanation{
+		Key: upname,
+	}
+	sensitive := w.readAsSecret(upname)
+	for _, p := range w.providers {
+		strval, ok := p.Lookup(upname)
+		if !ok {
+			continue
+		}
+		if sp, ok := p.(SensitiveProvider); ok && sp.Sensitive() {
+			sensitive = true
+		}
+		l := layerOf(p, upname, strval)
+		if res.Source == SourceNone && len(strval) > 0 {
+			res.Value, res.Source, res.Location = l.Value, l.Source, l.Location
+		} else {
+			res.Shadowed = append(res.Shadowed, l)
+		}
+	}
+	// Resolvers aren't run to explain without side effects.
+	if e := w.expander(upname); e.expansion && res.Source != SourceNone && !w.sensitive(res.Source) {
+		e.resolving = false
+		if strval, err := e.expand(res.Value); err == nil {
+			res.Value = strval
+		}
+		res.Refs = e.refs
+		sensitive = sensitive || e.sensitive
+	}
+	if sensitive {
+		res.Value = redacted
+		for i := range res.Shadowed {
+			res.Shadowed[i].Value = redacted
+		}
+	}
+	if res.Source == SourceNone {
+		w.mu.Lock()
+		if a, ok := w.accessed[upname]; ok && a.hasDef {
+			res.Value, res.Source = a.defval, SourceDefault
+			if sensitive {
+				res.Value = redacted
+			}
+		}
+		w.mu.Unlock()
+	}
+	return res
+}
+
+// Explains every variable accessed so far, one per line.
+// Sub wrappers only report keys within their prefix.
+func (w *env_wrapper) Report() string {
+	w.mu.Lock()
+	keys := append([]string{}, w.accessOrder...)
+	w.mu.Unlock()
+
+	var sb strings.Builder
+	for _, key := range keys {
+		if !strings.HasPrefix(key, w.prefix) {
+			continue
+		}
+		sb.WriteString(w.explain(key).String())
+		sb.WriteString("\n")
+	}
+	return sb.String()
+}
+
+// Describes the value of a key in a provider.
+func layerOf(p Provider, key, strval string) Layer {
+	res := Layer{
+		Source: Source(p.Name()),
+		Value:  strval,
+	}
+	if lp, ok := p.(LocatingProvider); ok {
+		res.Location = lp.Location(key)
+	}
+	return res
+}
@@ -0,0 +1,87 @@
+package env_wrapper
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestExplain(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "ENV_DB_PASSWORD"), []byte("hunter2"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	w := NewChain(
+		NewSecretsProvider(dir),
+		NewMapProvider("env", map[string]string{"DB_PASSWORD": "fallback", "HOST": "example.com", "EMPTY": ""}),
+		NewMapProvider("defaults", map[string]string{"HOST": "localhost", "EMPTY": "filled"}),
+	)
+	w.GetIntDef("PORT", 8080)
+	secretPath := filepath.Join(dir, "ENV_DB_PASSWORD")
+	tests := []struct {
+		name string
+		want Explanation
+		line string
+	}{
+		{
+			name: "host",
+			want: Explanation{Key: "HOST", Value: "example.com", Source: "env", Shadowed: []Layer{{Source: "defaults", Value: "localhost"}}},
+			line: `HOST="example.com" (env), shadows "localhost" (defaults)`,
+		},
+		{
+			name: "DB_PASSWORD",
+			want: Explanation{Key: "DB_PASSWORD", Value: redacted, Source: SourceSecret, Location: secretPath, Shadowed: []Layer{{Source: "env", Value: redacted}}},
+			line: `DB_PASSWORD="[REDACTED]" (secret: ` + secretPath + `), shadows "[REDACTED]" (env)`,
+		},
+		{
+			name: "EMPTY",
+			want: Explanation{Key: "EMPTY", Value: "filled", Source: "defaults", Shadowed: []Layer{{Source: "env", Value: ""}}},
+			line: `EMPTY="filled" (defaults), shadows "" (env)`,
+		},
+		{
+			name: "PORT",
+			want: Explanation{Key: "PORT", Value: "8080", Source: SourceDefault},
+			line: `PORT="8080" (default)`,
+		},
+		{
+			name: "MISSING",
+			want: Explanation{Key: "MISSING"},
+			line: "MISSING is unset",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := w.Explain(tt.name)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %+v, want %+v", got, tt.want)
+			}
+			if got.String() != tt.line {
+				t.Errorf("got %q, want %q", got.String(), tt.line)
+			}
+		})
+	}
+}
+
+func TestReport(t *testing.T) {
+	w := newTestWrapper(t, map[string]string{"TOKEN": "hunter2"}, map[string]string{"HOST": "db", "DB_HOST": "primary"})
+	w.GetString("HOST")
+	w.GetIntDef("PORT", 80)
+	w.GetString("token")
+	w.GetString("HOST")
+	w.Sub("db").GetString("HOST")
+
+	want := strings.Join([]string{
+		`HOST="db" (env)`,
+		`PORT="80" (default)`,
+		`TOKEN="[REDACTED]" (secret: ` + filepath.Join(w.providers[0].(*secretsProvider).path, "ENV_TOKEN") + `)`,
+		`DB_HOST="primary" (env)`,
+	}, "\n") + "\n"
+	if got := w.Report(); got != want {
+		t.Errorf("got\n%s\nwant\n%s", got, want)
+	}
+	if got := w.Sub("DB").Report(); got != "DB_HOST=\"primary\" (env)\n" {
+		t.Errorf("expected the sub report to be limited to its prefix, got %q", got)
+	}
+}
@@ -0,0 +1,121 @@
+package env_wrapper
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Suffix of variables which point to a file containing the value.
+const fileSuffix = "_FILE"
+
+// Value was read from a file referenced by a "_FILE" variable.
+const SourceFile Source = "file"
+
+type fileEnvProvider struct {
+	maxSize    int64
+	allowedDir string
+}
+
+// Creates a provider which reads the value of KEY from the file KEY_FILE points to.
+// This is the convention used by the official docker images.
+func NewFileEnvProvider(opts ...Option) *fileEnvProvider {
+	o := newOptions(opts)
+	return &fileEnvProvider{
+		o.fileMaxSize,
+		o.fileAllowedDir,
+	}
+}
+
+func (p *fileEnvProvider) Name() string {
+	return string(SourceFile)
+}
+
+func (p *fileEnvProvider) Lookup(key string) (string, bool) {
+	res, ok, err := p.LookupE(key)
+	return res, ok && err == nil
+}
+
+// Reports files which can't be read, are too large or outside of the allowed directory.
+func (p *fileEnvProvider) LookupE(key string) (string, bool, error) {
+	path := strings.TrimSpace(os.Getenv(key + fileSuffix))
+	if len(path) == 0 {
+		return "", false, nil
+	}
+	bval, err := p.read(path)
+	if err != nil {
+		return "", true, fmt.Errorf("%s: %w", key+fileSuffix, err)
+	}
+	return strings.TrimSpace(string(bval)), true, nil
+}
+
+func (p *fileEnvProvider) Sensitive() bool {
+	return true
+}
+
+// Gets the referenced file and the variable which references it.
+func (p *fileEnvProvider) Location(key string) string {
+	return fmt.Sprintf("%s (%s)", strings.TrimSpace(os.Getenv(key+fileSuffix)), key+fileSuffix)
+}
+
+func (p *fileEnvProvider) Keys() []string {
+	res := []string{}
+	for _, kv := range os.Environ() {
+		if i := strings.IndexByte(kv, '='); i > 0 && strings.HasSuffix(kv[:i], fileSuffix) {
+			res = append(res, strings.ToUpper(strings.TrimSuffix(kv[:i], fileSuffix)))
+		}
+	}
+	return res
+}
+
+// Reads a referenced file while enforcing the directory and size limits.
+func (p *fileEnvProvider) read(path string) ([]byte, error) {
+	path, err := filepath.EvalSymlinks(path)
+	if err != nil {
+		return nil, err
+	}
+	if len(p.allowedDir) > 0 {
+		if err := checkInside(p.allowedDir, path); err != nil {
+			return nil, err
+		}
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	if p.maxSize <= 0 {
+		return io.ReadAll(file)
+	}
+	bval, err := io.ReadAll(io.LimitReader(file, p.maxSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(bval)) > p.maxSize {
+		return nil, ErrFileTooLarge
+	}
+	return bval, nil
+}
+
+// Returns ErrOutsideDir if the resolved path isn't located inside dir.
+func checkInside(dir, path string) error {
+	dir, err := filepath.EvalSymlinks(dir)
+	if err != nil {
+		return err
+	}
+	dir, err = filepath.Abs(dir)
+	if err != nil {
+		return err
+	}
+	path, err = filepath.Abs(path)
+	if err != nil {
+		return err
+	}
+	rel, err := filepath.Rel(dir, path)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return ErrOutsideDir
+	}
+	return nil
+}
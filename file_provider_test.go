@@ -0,0 +1,88 @@
+package env_wrapper
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestFileEnvProviderLimits(t *testing.T) {
+	dir := t.TempDir()
+	allowed := filepath.Join(dir, "allowed")
+	if err := os.Mkdir(allowed, 0o700); err != nil {
+		t.Fatal(err)
+	}
+	files := map[string]string{
+		filepath.Join(dir, "outside.txt"):   "topsecret",
+		filepath.Join(allowed, "large.txt"): "0123456789abcdef",
+		filepath.Join(allowed, "ok.txt"):    " fine\n",
+	}
+	for path, value := range files {
+		if err := os.WriteFile(path, []byte(value), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	t.Setenv("FILE_TEST_OUTSIDE_FILE", filepath.Join(dir, "outside.txt"))
+	t.Setenv("FILE_TEST_LARGE_FILE", filepath.Join(allowed, "large.txt"))
+	t.Setenv("FILE_TEST_OK_FILE", filepath.Join(allowed, "ok.txt"))
+	t.Setenv("FILE_TEST_MISSING_FILE", filepath.Join(allowed, "missing.txt"))
+
+	tests := []struct {
+		name string
+		want string
+		err  error
+	}{
+		{"FILE_TEST_OK", "fine", nil},
+		{"FILE_TEST_OUTSIDE", "", ErrOutsideDir},
+		{"FILE_TEST_LARGE", "", ErrFileTooLarge},
+		{"FILE_TEST_MISSING", "", os.ErrNotExist},
+		{"FILE_TEST_UNSET", "", ErrNotSet},
+	}
+	w := NewChain(NewFileEnvProvider(FileAllowedDir(allowed), FileMaxSize(8)))
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := w.GetStringE(tt.name)
+			if !errors.Is(err, tt.err) || got != tt.want {
+				t.Fatalf("expected %q, %v, got %q, %v", tt.want, tt.err, got, err)
+			}
+			if err != nil && strings.Contains(err.Error(), "topsecret") {
+				t.Fatalf("error leaks the file content: %v", err)
+			}
+		})
+	}
+	if got := w.GetStringDef("FILE_TEST_OUTSIDE", "def"); got != "def" {
+		t.Errorf("expected the default, got %q", got)
+	}
+}
+
+func TestLookupRejectedFile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "large.txt")
+	if err := os.WriteFile(path, []byte("0123456789abcdef"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("FILE_TEST_LARGE_FILE", path)
+	w := NewChain(NewFileEnvProvider(FileMaxSize(8)))
+
+	strval, src, err := w.LookupSourceE("FILE_TEST_LARGE")
+	if !errors.Is(err, ErrFileTooLarge) || src != SourceFile || len(strval) > 0 {
+		t.Errorf("expected ErrFileTooLarge from the file layer, got %q, %q, %v", strval, src, err)
+	}
+	if _, ok, err := w.LookupStringE("FILE_TEST_LARGE"); !ok || !errors.Is(err, ErrFileTooLarge) {
+		t.Errorf("expected a present value with ErrFileTooLarge, got %v, %v", ok, err)
+	}
+	if _, ok, err := w.LookupStringArraySepE("FILE_TEST_LARGE", ","); !ok || !errors.Is(err, ErrFileTooLarge) {
+		t.Errorf("expected a present value with ErrFileTooLarge, got %v, %v", ok, err)
+	}
+	if _, ok, err := w.LookupInt("FILE_TEST_LARGE"); !ok || !errors.Is(err, ErrFileTooLarge) {
+		t.Errorf("expected ErrFileTooLarge, got %v, %v", ok, err)
+	}
+	if _, ok := w.LookupString("FILE_TEST_LARGE"); ok {
+		t.Error("expected LookupString to report an unreadable value as absent")
+	}
+	if _, ok, err := w.LookupStringE("FILE_TEST_UNSET"); ok || err != nil {
+		t.Errorf("expected an absent value without error, got %v, %v", ok, err)
+	}
+}
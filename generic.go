@@ -0,0 +1,97 @@
+package env_wrapper
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// Gets a value of type T or returns a default value if the variable doesn't exist, can't be parsed or violates a rule.
+// Supported are the types of the typed getters, types registered with RegisterParser
+// and types implementing encoding.TextUnmarshaler.
+func Get[T any](w *env_wrapper, name string, defval T, rules ...Rule) T {
+	res, err := GetDefE(w, name, defval, rules...)
+	if err != nil {
+		return defval
+	}
+	return res
+}
+
+// Gets a value of type T or returns an error if the variable doesn't exist, can't be parsed or violates a rule.
+func GetE[T any](w *env_wrapper, name string, rules ...Rule) (T, error) {
+	w.trackSecretType(name, reflect.TypeOf((*T)(nil)).Elem())
+	r, err := w.resolve(name)
+	w.record(name, typeName[T](), nil, r.source)
+	if err != nil {
+		var res T
+		return res, err
+	}
+	if err := w.validate(name, r, rules); err != nil {
+		var res T
+		return res, err
+	}
+	if r.source == SourceNone {
+		var res T
+		return res, w.valueError(name, r, ErrNotSet)
+	}
+	return parseAs[T](w, name, r)
+}
+
+// Gets a value of type T or returns a default value if the variable doesn't exist.
+// Returns an error if the value can't be parsed or violates a rule.
+func GetDefE[T any](w *env_wrapper, name string, defval T, rules ...Rule) (T, error) {
+	w.trackSecretType(name, reflect.TypeOf((*T)(nil)).Elem())
+	defstr := fmt.Sprint(defval)
+	w.trackDefault(name, defstr)
+	r, err := w.resolve(name)
+	w.record(name, typeName[T](), &defstr, r.source)
+	if err != nil {
+		return defval, err
+	}
+	if err := w.validate(name, r, rules); err != nil {
+		return defval, err
+	}
+	if r.source == SourceNone {
+		return defval, nil
+	}
+	return parseAs[T](w, name, r)
+}
+
+// Gets a value of type T and reports if the variable is present.
+// Returns an error if a present value can't be read or parsed.
+func Lookup[T any](w *env_wrapper, name string) (T, bool, error) {
+	res, src, err := LookupSourceOf[T](w, name)
+	return res, src != SourceNone, err
+}
+
+// Gets a value of type T and the layer which supplied it.
+// Returns SourceNone if the variable isn't present.
+// Returns a ValueError with the supplying layer if a present value can't be read or parsed.
+func LookupSourceOf[T any](w *env_wrapper, name string) (T, Source, error) {
+	w.trackSecretType(name, reflect.TypeOf((*T)(nil)).Elem())
+	r, err := w.resolvePresent(name)
+	w.record(name, typeName[T](), nil, r.source)
+	if err != nil {
+		var res T
+		return res, r.source, err
+	}
+	if r.source == SourceNone {
+		var res T
+		return res, SourceNone, nil
+	}
+	res, err := parseAs[T](w, name, r)
+	return res, r.source, err
+}
+
+// Parses a value into T and wraps parse errors.
+func parseAs[T any](w *env_wrapper, name string, r resolved) (T, error) {
+	var res T
+	parser, err := parserFor[T]()
+	if err == nil {
+		res, err = parser(r.value)
+	}
+	if err != nil {
+		var zero T
+		return zero, w.valueError(name, r, err)
+	}
+	return res, nil
+}
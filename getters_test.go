@@ -0,0 +1,162 @@
+package env_wrapper
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestGetDefaults(t *testing.T) {
+	w := newTestWrapper(t,
+		map[string]string{
+			"S_PRIO":    "secret",
+			"I_PRIO":    "7\n",
+			"B_PRIO":    "false",
+			"I_BADSEC":  "x",
+			"S_EMPTYSE": "",
+		},
+		map[string]string{
+			"S_PRIO":    "env",
+			"I_PRIO":    "8",
+			"B_PRIO":    "true",
+			"I_BADSEC":  "9",
+			"S_EMPTYSE": "env",
+			"S_VALUE":   " value ",
+			"S_EMPTY":   "",
+			"S_SPACE":   "   ",
+			"I_VALUE":   " 42 ",
+			"I_NEG":     "-3",
+			"I_INVALID": "4x",
+			"I_FLOAT":   "1.5",
+			"I_EMPTY":   "",
+			"I_SPACE":   " \t ",
+			"I_OVER":    "99999999999999999999",
+			"B_VALUE":   "true",
+			"B_SHORT":   "0",
+			"B_INVALID": "yes",
+			"B_EMPTY":   "",
+			"B_SPACE":   "  ",
+		},
+	)
+
+	strs := []struct {
+		name string
+		want string
+	}{
+		{"S_VALUE", "value"},
+		{"s_value", "value"},
+		{"S_EMPTY", "def"},
+		{"S_SPACE", "def"},
+		{"S_MISSING", "def"},
+		{"S_PRIO", "secret"},
+		{"S_EMPTYSE", "env"},
+	}
+	for _, tt := range strs {
+		if got := w.GetStringDef(tt.name, "def"); got != tt.want {
+			t.Errorf("GetStringDef(%q) = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+
+	ints := []struct {
+		name string
+		want int
+	}{
+		{"I_VALUE", 42},
+		{"I_NEG", -3},
+		{"I_INVALID", 5},
+		{"I_FLOAT", 5},
+		{"I_EMPTY", 5},
+		{"I_SPACE", 5},
+		{"I_OVER", 5},
+		{"I_MISSING", 5},
+		{"I_PRIO", 7},
+		{"I_BADSEC", 5},
+	}
+	for _, tt := range ints {
+		if got := w.GetIntDef(tt.name, 5); got != tt.want {
+			t.Errorf("GetIntDef(%q) = %d, want %d", tt.name, got, tt.want)
+		}
+	}
+
+	bools := []struct {
+		name string
+		want bool
+	}{
+		{"B_VALUE", true},
+		{"B_SHORT", false},
+		{"B_INVALID", true},
+		{"B_EMPTY", true},
+		{"B_SPACE", true},
+		{"B_MISSING", true},
+		{"B_PRIO", false},
+	}
+	for _, tt := range bools {
+		if got := w.GetBoolDef(tt.name, true); got != tt.want {
+			t.Errorf("GetBoolDef(%q) = %t, want %t", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestGetDefaultsEnvironment(t *testing.T) {
+	t.Setenv("GD_INT", " 12 ")
+	t.Setenv("GD_SPACE", "  ")
+	t.Setenv("GD_BOOL", "T")
+	w := New(t.TempDir())
+	if got := w.GetIntDef("gd_int", 1); got != 12 {
+		t.Errorf("expected a trimmed value, got %d", got)
+	}
+	if got := w.GetStringDef("GD_SPACE", "def"); got != "def" {
+		t.Errorf("expected the default for a whitespace value, got %q", got)
+	}
+	if got := w.GetBoolDef("GD_BOOL", false); !got {
+		t.Error("expected true")
+	}
+}
+
+func TestGetErrors(t *testing.T) {
+	w := newTestWrapper(t,
+		map[string]string{"S_PORT": "80a"},
+		map[string]string{"PORT": "80a", "DEBUG": "maybe", "NAME": "svc", "S_PORT": "80"},
+	)
+	tests := []struct {
+		name   string
+		get    func(string) error
+		key    string
+		value  string
+		source Source
+		err    error
+	}{
+		{"PORT", func(n string) error { _, err := w.GetIntE(n); return err }, "PORT", "80a", Source("env"), strconv.ErrSyntax},
+		{"port", func(n string) error { _, err := w.GetIntDefE(n, 1); return err }, "PORT", "80a", Source("env"), strconv.ErrSyntax},
+		{"DEBUG", func(n string) error { _, err := w.GetBoolE(n); return err }, "DEBUG", "maybe", Source("env"), strconv.ErrSyntax},
+		{"MISSING", func(n string) error { _, err := w.GetStringE(n); return err }, "MISSING", "", SourceNone, ErrNotSet},
+		{"MISSING", func(n string) error { _, err := w.GetIntE(n); return err }, "MISSING", "", SourceNone, ErrNotSet},
+		{"S_PORT", func(n string) error { _, err := w.GetIntE(n); return err }, "S_PORT", redacted, SourceSecret, strconv.ErrSyntax},
+		{"NAME", func(n string) error { _, err := w.GetStringE(n); return err }, "", "", SourceNone, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.get(tt.name)
+			if tt.err == nil {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			var verr *ValueError
+			if !errors.As(err, &verr) || !errors.Is(err, tt.err) {
+				t.Fatalf("expected a ValueError wrapping %v, got %v", tt.err, err)
+			}
+			if verr.Key != tt.key || verr.Value != tt.value || verr.Source != tt.source {
+				t.Errorf("got %s=%q (%s), want %s=%q (%s)", verr.Key, verr.Value, verr.Source, tt.key, tt.value, tt.source)
+			}
+			if tt.source == SourceSecret && strings.Contains(err.Error(), "80a") {
+				t.Errorf("secret value leaked in %v", err)
+			}
+		})
+	}
+	if got := w.GetIntDef("PORT", 1); got != 1 {
+		t.Errorf("expected the default for an invalid value, got %d", got)
+	}
+}
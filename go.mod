@@ -1,5 +1,5 @@
 module github.com/kwitsch/go-env_wrapper
 
-go 1.16
+go 1.18
 
 require github.com/ijustfool/docker-secrets v0.0.0-20191021062307-b25ea5007562
@@ -0,0 +1,129 @@
+package env_wrapper
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// Describes invalid JSON, Offset is the byte offset in the value.
+type JSONError struct {
+	Offset int64
+	Err    error
+}
+
+func (e *JSONError) Error() string {
+	return fmt.Sprintf("invalid JSON at byte %d: %v", e.Offset, e.Err)
+}
+
+func (e *JSONError) Unwrap() error {
+	return e.Err
+}
+
+// Decodes a JSON value into the value dst points to.
+// Returns an error if the variable doesn't exist or can't be decoded.
+func (w *env_wrapper) GetJSON(name string, dst any) error {
+	r, err := w.resolve(name)
+	w.record(name, strings.TrimPrefix(fmt.Sprintf("%T", dst), "*"), nil, r.source)
+	if err != nil {
+		return err
+	}
+	if r.source == SourceNone {
+		return w.valueError(name, r, ErrNotSet)
+	}
+	if err := decodeJSON([]byte(r.value), dst); err != nil {
+		return w.valueError(name, r, err)
+	}
+	return nil
+}
+
+// Decodes the part of a JSON value a JSON Pointer (RFC 6901) like "/0/host" refers to.
+// Returns an error if the variable doesn't exist, can't be decoded or the pointer doesn't resolve.
+func (w *env_wrapper) GetJSONPath(name, pointer string, dst any) error {
+	r, err := w.resolve(name)
+	w.record(name, strings.TrimPrefix(fmt.Sprintf("%T", dst), "*"), nil, r.source)
+	if err != nil {
+		return err
+	}
+	if r.source == SourceNone {
+		return w.valueError(name, r, ErrNotSet)
+	}
+	var doc any
+	if err := decodeJSON([]byte(r.value), &doc); err != nil {
+		return w.valueError(name, r, err)
+	}
+	part, err := resolvePointer(doc, pointer)
+	if err != nil {
+		return w.valueError(name, r, err)
+	}
+	bval, err := json.Marshal(part)
+	if err == nil {
+		err = json.Unmarshal(bval, dst)
+	}
+	if err != nil {
+		return w.valueError(name, r, fmt.Errorf("at %s: %w", pointer, err))
+	}
+	return nil
+}
+
+// Decodes JSON and adds the byte offset to syntax, type, truncation and trailing data errors.
+// Numbers are kept as json.Number to avoid precision loss.
+func decodeJSON(bval []byte, dst any) error {
+	dec := json.NewDecoder(bytes.NewReader(bval))
+	dec.UseNumber()
+	err := dec.Decode(dst)
+	if err == nil {
+		end := dec.InputOffset()
+		if _, err := dec.Token(); err != io.EOF {
+			rest := bval[end:]
+			end += int64(len(rest) - len(bytes.TrimLeft(rest, " \t\r\n")))
+			return &JSONError{end, errors.New("unexpected data after value")}
+		}
+	}
+	var serr *json.SyntaxError
+	var terr *json.UnmarshalTypeError
+	switch {
+	case errors.As(err, &serr):
+		return &JSONError{serr.Offset, err}
+	case errors.As(err, &terr):
+		return &JSONError{terr.Offset, err}
+	case errors.Is(err, io.ErrUnexpectedEOF):
+		return &JSONError{int64(len(bval)), err}
+	}
+	return err
+}
+
+// Resolves a JSON Pointer in a decoded document.
+func resolvePointer(doc any, pointer string) (any, error) {
+	if len(pointer) == 0 {
+		return doc, nil
+	}
+	if !strings.HasPrefix(pointer, "/") {
+		return nil, fmt.Errorf("invalid JSON pointer %q", pointer)
+	}
+	res := doc
+	for _, token := range strings.Split(pointer[1:], "/") {
+		token = strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+		switch node := res.(type) {
+		case map[string]any:
+			val, ok := node[token]
+			if !ok {
+				return nil, fmt.Errorf("JSON pointer %s: key %q not found", pointer, token)
+			}
+			res = val
+		case []any:
+			i, err := strconv.Atoi(token)
+			if err != nil || i < 0 || i >= len(node) {
+				return nil, fmt.Errorf("JSON pointer %s: invalid index %q", pointer, token)
+			}
+			res = node[i]
+		default:
+			return nil, fmt.Errorf("JSON pointer %s: %q can't be resolved in a scalar", pointer, token)
+		}
+	}
+	return res, nil
+}
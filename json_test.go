@@ -0,0 +1,76 @@
+package env_wrapper
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestDecodeJSON(t *testing.T) {
+	tests := []struct {
+		value  string
+		want   any
+		offset int64
+	}{
+		{`{"a":1}`, map[string]any{"a": json.Number("1")}, -1},
+		{` [1, 2] `, []any{json.Number("1"), json.Number("2")}, -1},
+		{`{"a":1}}`, nil, 7},
+		{`{"a":1} ]`, nil, 8},
+		{`{"a":1} {"b":2}`, nil, 8},
+		{`[1] x`, nil, 4},
+		{`{"a":1,,}`, nil, 8},
+		{`{"a":`, nil, 5},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			var got any
+			err := decodeJSON([]byte(tt.value), &got)
+			if tt.offset >= 0 {
+				var jerr *JSONError
+				if !errors.As(err, &jerr) || jerr.Offset != tt.offset {
+					t.Fatalf("expected a JSONError at byte %d, got %v", tt.offset, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestGetJSONPath(t *testing.T) {
+	w := newTestWrapper(t, nil, map[string]string{
+		"ROUTES": `[{"host":"a","port":1},{"host":"b","port":2}]`,
+		"TILDE":  `{"a/b":{"c~d":"x"}}`,
+	})
+	tests := []struct {
+		name    string
+		pointer string
+		want    string
+		err     bool
+	}{
+		{"ROUTES", "/1/host", "b", false},
+		{"TILDE", "/a~1b/c~0d", "x", false},
+		{"ROUTES", "/2/host", "", true},
+		{"ROUTES", "/0/missing", "", true},
+		{"ROUTES", "/0/host/x", "", true},
+		{"ROUTES", "0", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.pointer, func(t *testing.T) {
+			var got string
+			err := w.GetJSONPath(tt.name, tt.pointer, &got)
+			if (err != nil) != tt.err {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Fatalf("expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,49 @@
+package env_wrapper
+
+import (
+	"sort"
+	"strings"
+)
+
+// Lists the keys of every enumerable provider in sorted order.
+// Sub wrappers only list keys within their prefix and return them without it.
+func (w *env_wrapper) Keys() []string {
+	return w.KeysWithPrefix("")
+}
+
+// Lists the keys starting with a prefix in sorted order.
+// The keys are returned relative to the wrapper and can be passed to the getters.
+func (w *env_wrapper) KeysWithPrefix(prefix string) []string {
+	full := w.key(prefix)
+	seen := make(map[string]bool)
+	res := []string{}
+	for _, p := range w.providers {
+		ep, ok := p.(EnumeratingProvider)
+		if !ok {
+			continue
+		}
+		for _, key := range ep.Keys() {
+			if !strings.HasPrefix(key, full) || seen[key] {
+				continue
+			}
+			seen[key] = true
+			res = append(res, strings.TrimPrefix(key, w.prefix))
+		}
+	}
+	sort.Strings(res)
+	return res
+}
+
+// Gets every non empty variable starting with a prefix.
+// The map keys are stripped of the prefix, the values follow the usual precedence.
+// For example "TENANT_" returns "A_URL" for the variable "TENANT_A_URL".
+func (w *env_wrapper) GetStringMapByPrefix(prefix string) map[string]string {
+	res := make(map[string]string)
+	upprefix := strings.ToUpper(prefix)
+	for _, key := range w.KeysWithPrefix(prefix) {
+		if r, err := w.resolve(key); err == nil && r.source != SourceNone {
+			res[strings.TrimPrefix(key, upprefix)] = r.value
+		}
+	}
+	return res
+}
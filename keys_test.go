@@ -0,0 +1,57 @@
+package env_wrapper
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestKeys(t *testing.T) {
+	w := newTestWrapper(t,
+		map[string]string{"FEATURE_A": "secret", "TENANT_X_TOKEN": "t"},
+		map[string]string{
+			"FEATURE_A":      "env",
+			"feature_b":      "on",
+			"FEATURE_EMPTY":  "",
+			"FEATURES":       "all",
+			"TENANT_X_URL":   "http://x",
+			"TENANT_Y_URL":   "http://y",
+			"OTHER":          "o",
+			"TENANT_X_EMPTY": "",
+		},
+	)
+	tests := []struct {
+		view   *env_wrapper
+		prefix string
+		want   []string
+	}{
+		{w, "FEATURE_", []string{"FEATURE_A", "FEATURE_B", "FEATURE_EMPTY"}},
+		{w, "feature", []string{"FEATURES", "FEATURE_A", "FEATURE_B", "FEATURE_EMPTY"}},
+		{w, "MISSING_", []string{}},
+		{w.Sub("TENANT"), "X_", []string{"X_EMPTY", "X_TOKEN", "X_URL"}},
+		{w.Sub("TENANT").Sub("Y"), "", []string{"URL"}},
+	}
+	for _, tt := range tests {
+		if got := tt.view.KeysWithPrefix(tt.prefix); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s%s: got %v, want %v", tt.view.prefix, tt.prefix, got, tt.want)
+		}
+	}
+	if keys := w.Sub("feature").Keys(); !reflect.DeepEqual(keys, []string{"A", "B", "EMPTY"}) {
+		t.Errorf("expected keys relative to the view, got %v", keys)
+	}
+
+	maps := []struct {
+		view   *env_wrapper
+		prefix string
+		want   map[string]string
+	}{
+		{w, "FEATURE_", map[string]string{"A": "secret", "B": "on"}},
+		{w, "tenant_", map[string]string{"X_TOKEN": "t", "X_URL": "http://x", "Y_URL": "http://y"}},
+		{w.Sub("TENANT"), "X_", map[string]string{"TOKEN": "t", "URL": "http://x"}},
+		{w, "MISSING_", map[string]string{}},
+	}
+	for _, tt := range maps {
+		if got := tt.view.GetStringMapByPrefix(tt.prefix); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s%s: got %v, want %v", tt.view.prefix, tt.prefix, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,215 @@
+package env_wrapper
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// Describes an element of a list which couldn't be parsed.
+// Index is the position of the element in the value, dropped empty elements are counted as well.
+type ListError struct {
+	Index int
+	Err   error
+}
+
+func (e *ListError) Error() string {
+	return fmt.Sprintf("element %d: %v", e.Index, e.Err)
+}
+
+func (e *ListError) Unwrap() error {
+	return e.Err
+}
+
+// Splits a value with a seperator honoring double quotes and backslash escapes.
+// Unquoted elements are trimmed and dropped if empty, quoted elements are kept as they are.
+// A seperator can be part of an element if it is quoted or escaped.
+func SplitList(strval, seperator string) ([]string, error) {
+	res, _, err := splitList(strval, seperator)
+	return res, err
+}
+
+// Splits a value like SplitList and returns the position of each element in the value as well.
+func splitList(strval, seperator string) ([]string, []int, error) {
+	res := []string{}
+	pos := []int{}
+	var sb strings.Builder
+	quoted := false
+	elem := 0
+	finish := func() {
+		if quoted {
+			res = append(res, sb.String())
+			pos = append(pos, elem)
+		} else if s := strings.TrimSpace(sb.String()); len(s) > 0 {
+			res = append(res, s)
+			pos = append(pos, elem)
+		}
+		sb.Reset()
+		quoted = false
+		elem++
+	}
+	for i := 0; i < len(strval); {
+		if len(seperator) > 0 && strings.HasPrefix(strval[i:], seperator) {
+			finish()
+			i += len(seperator)
+			continue
+		}
+		switch c := strval[i]; {
+		case quoted:
+			if c != ' ' && c != '\t' {
+				return nil, nil, &ListError{elem, errors.New("unexpected character after closing quote")}
+			}
+			i++
+		case c == '\\':
+			if i+1 >= len(strval) {
+				return nil, nil, &ListError{elem, errors.New("trailing backslash")}
+			}
+			_, size := utf8.DecodeRuneInString(strval[i+1:])
+			sb.WriteString(strval[i+1 : i+1+size])
+			i += 1 + size
+		case c == '"':
+			if len(strings.TrimSpace(sb.String())) > 0 {
+				return nil, nil, &ListError{elem, errors.New("unexpected quote inside element")}
+			}
+			sb.Reset()
+			n, err := readQuoted(strval[i+1:], &sb)
+			if err != nil {
+				return nil, nil, &ListError{elem, err}
+			}
+			quoted = true
+			i += 1 + n
+		default:
+			sb.WriteByte(c)
+			i++
+		}
+	}
+	finish()
+	return res, pos, nil
+}
+
+// Reads a quoted element up to and including the closing quote.
+// Returns the number of consumed bytes.
+func readQuoted(strval string, sb *strings.Builder) (int, error) {
+	for i := 0; i < len(strval); {
+		switch strval[i] {
+		case '"':
+			return i + 1, nil
+		case '\\':
+			if i+1 >= len(strval) {
+				return 0, errors.New("unterminated quote")
+			}
+			_, size := utf8.DecodeRuneInString(strval[i+1:])
+			sb.WriteString(strval[i+1 : i+1+size])
+			i += 1 + size
+		default:
+			sb.WriteByte(strval[i])
+			i++
+		}
+	}
+	return 0, errors.New("unterminated quote")
+}
+
+// Gets a string list split by commas with SplitList or returns an empty list if the variable doesn't exist or is malformed.
+func (w *env_wrapper) GetStringList(name string) []string {
+	return w.GetStringListSep(name, ",")
+}
+
+// Gets a string list split by a seperator with SplitList or returns an empty list if the variable doesn't exist or is malformed.
+func (w *env_wrapper) GetStringListSep(name, seperator string) []string {
+	res, err := GetListSepE[string](w, name, seperator)
+	if err != nil {
+		return []string{}
+	}
+	return res
+}
+
+// Gets a string list split by commas with SplitList.
+// Returns an error if the variable doesn't exist or is malformed.
+func (w *env_wrapper) GetStringListE(name string) ([]string, error) {
+	return GetListSepE[string](w, name, ",")
+}
+
+// Gets a string list split by a seperator with SplitList.
+// Returns an error if the variable doesn't exist or is malformed.
+func (w *env_wrapper) GetStringListSepE(name, seperator string) ([]string, error) {
+	return GetListSepE[string](w, name, seperator)
+}
+
+// Gets a integer list split by commas or returns an empty list if the variable doesn't exist or is malformed.
+func (w *env_wrapper) GetIntList(name string) []int {
+	res, err := w.GetIntListE(name)
+	if err != nil {
+		return []int{}
+	}
+	return res
+}
+
+// Gets a integer list split by commas.
+// Returns an error with the element index if the variable doesn't exist, is malformed or an element can't be parsed.
+func (w *env_wrapper) GetIntListE(name string) ([]int, error) {
+	return GetListSepE[int](w, name, ",")
+}
+
+// Gets a floating point list split by commas or returns an empty list if the variable doesn't exist or is malformed.
+func (w *env_wrapper) GetFloat64List(name string) []float64 {
+	res, err := w.GetFloat64ListE(name)
+	if err != nil {
+		return []float64{}
+	}
+	return res
+}
+
+// Gets a floating point list split by commas.
+// Returns an error with the element index if the variable doesn't exist, is malformed or an element can't be parsed.
+func (w *env_wrapper) GetFloat64ListE(name string) ([]float64, error) {
+	return GetListSepE[float64](w, name, ",")
+}
+
+// Gets a duration list split by commas or returns an empty list if the variable doesn't exist or is malformed.
+func (w *env_wrapper) GetDurationList(name string) []time.Duration {
+	res, err := w.GetDurationListE(name)
+	if err != nil {
+		return []time.Duration{}
+	}
+	return res
+}
+
+// Gets a duration list split by commas.
+// Returns an error with the element index if the variable doesn't exist, is malformed or an element can't be parsed.
+func (w *env_wrapper) GetDurationListE(name string) ([]time.Duration, error) {
+	return GetListSepE[time.Duration](w, name, ",")
+}
+
+// Gets a list with elements of type T split by a seperator with SplitList.
+// Returns an error with the element index if the variable doesn't exist, is malformed or an element can't be parsed.
+func GetListSepE[T any](w *env_wrapper, name, seperator string) ([]T, error) {
+	r, err := w.resolve(name)
+	w.record(name, typeName[[]T](), nil, r.source)
+	if err != nil {
+		return []T{}, err
+	}
+	if r.source == SourceNone {
+		return []T{}, w.valueError(name, r, ErrNotSet)
+	}
+	parser, err := parserFor[T]()
+	if err != nil {
+		return []T{}, w.valueError(name, r, err)
+	}
+	parts, pos, err := splitList(r.value, seperator)
+	if err != nil {
+		return []T{}, w.valueError(name, r, err)
+	}
+	res := make([]T, len(parts))
+	for i, part := range parts {
+		if res[i], err = parser(part); err != nil {
+			err = numErrCause(err)
+			if r.sensitive {
+				err = redactError(err, part)
+			}
+			return []T{}, w.valueError(name, r, &ListError{pos[i], err})
+		}
+	}
+	return res, nil
+}
@@ -0,0 +1,126 @@
+package env_wrapper
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSplitList(t *testing.T) {
+	tests := []struct {
+		value     string
+		seperator string
+		want      []string
+		err       string
+	}{
+		{"", ",", []string{}, ""},
+		{"a", ",", []string{"a"}, ""},
+		{"a,b,c", ",", []string{"a", "b", "c"}, ""},
+		{" a , b ,, c ,", ",", []string{"a", "b", "c"}, ""},
+		{`"a,b",c`, ",", []string{"a,b", "c"}, ""},
+		{`" a ",""`, ",", []string{" a ", ""}, ""},
+		{`a\,b,c`, ",", []string{"a,b", "c"}, ""},
+		{`"say \"hi\"",x`, ",", []string{`say "hi"`, "x"}, ""},
+		{`a\\,b`, ",", []string{`a\`, "b"}, ""},
+		{`\ä,b`, ",", []string{"ä", "b"}, ""},
+		{"a::b::c", "::", []string{"a", "b", "c"}, ""},
+		{`"a::b"::c`, "::", []string{"a::b", "c"}, ""},
+		{"a b  c", " ", []string{"a", "b", "c"}, ""},
+		{`"a" ,b`, ",", []string{"a", "b"}, ""},
+		{`"a"x,b`, ",", nil, "element 0: unexpected character after closing quote"},
+		{`a"b",c`, ",", nil, "element 0: unexpected quote inside element"},
+		{`a,"b`, ",", nil, "element 1: unterminated quote"},
+		{`a,b\`, ",", nil, "element 1: trailing backslash"},
+		{`a,,"b`, ",", nil, "element 2: unterminated quote"},
+		{`a, ,"x" y`, ",", nil, "element 2: unexpected character after closing quote"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, err := SplitList(tt.value, tt.seperator)
+			if len(tt.err) > 0 {
+				if err == nil || err.Error() != tt.err {
+					t.Fatalf("expected error %q, got %v", tt.err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestGetListIndex(t *testing.T) {
+	w := newTestWrapper(t, nil, map[string]string{"A": "1,,x", "B": ",2, ,3,y", "C": "1,2,3"})
+	tests := []struct {
+		name string
+		err  string
+	}{
+		{"A", "element 2"},
+		{"B", "element 4"},
+		{"C", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := w.GetIntListE(tt.name)
+			if len(tt.err) == 0 {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			var lerr *ListError
+			if err == nil || !strings.Contains(err.Error(), tt.err) || !errors.As(err, &lerr) {
+				t.Errorf("expected a ListError with %q, got %v", tt.err, err)
+			}
+		})
+	}
+}
+
+func TestGetStringArray(t *testing.T) {
+	w := newTestWrapper(t, nil, map[string]string{
+		"PATHS":  `/tmp "/my docs" /usr\ local`,
+		"REGEX":  `"a,b",c`,
+		"QUOTE":  `a"b,c`,
+		"SPACES": "  a   b ",
+	})
+	tests := []struct {
+		name      string
+		seperator string
+		want      []string
+	}{
+		{"PATHS", " ", []string{"/tmp", "/my docs", "/usr local"}},
+		{"REGEX", ",", []string{"a,b", "c"}},
+		{"QUOTE", ",", []string{`a"b`, "c"}},
+		{"SPACES", " ", []string{"a", "b"}},
+		{"MISSING", " ", []string{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := w.GetStringArraySep(tt.name, tt.seperator); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("expected %q, got %q", tt.want, got)
+			}
+			got, _ := w.LookupStringArraySep(tt.name, tt.seperator)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("expected %q from the lookup, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestGetListRedaction(t *testing.T) {
+	w := newTestWrapper(t, map[string]string{"TLIST": "1s,hunter2"}, map[string]string{"PLIST": "1s,5x"})
+
+	_, err := w.GetDurationListE("TLIST")
+	if err == nil || strings.Contains(err.Error(), "hunter2") || !strings.Contains(err.Error(), "element 1") {
+		t.Errorf("expected a redacted error, got %v", err)
+	}
+	_, err = w.GetDurationListE("PLIST")
+	if err == nil || !strings.Contains(err.Error(), "5x") {
+		t.Errorf("expected the plain value in the error, got %v", err)
+	}
+}
@@ -0,0 +1,88 @@
+package env_wrapper
+
+// Gets a string value and reports if the variable is present.
+// Empty secrets and empty environment variables count as present.
+// Values which can't be read are reported as absent, use LookupStringE to tell them apart.
+func (w *env_wrapper) LookupString(name string) (string, bool) {
+	strval, src := w.LookupSource(name)
+	return strval, src != SourceNone
+}
+
+// Gets a string value and reports if the variable is present.
+// Returns a ValueError if a present value can't be read, for example a rejected "_FILE" reference.
+func (w *env_wrapper) LookupStringE(name string) (string, bool, error) {
+	strval, src, err := w.LookupSourceE(name)
+	return strval, src != SourceNone, err
+}
+
+// Gets a string value and the layer which supplied it.
+// Returns SourceNone if the variable isn't present or can't be read, use LookupSourceE to tell them apart.
+func (w *env_wrapper) LookupSource(name string) (string, Source) {
+	strval, src, err := w.LookupSourceE(name)
+	if err != nil {
+		return "", SourceNone
+	}
+	return strval, src
+}
+
+// Gets a string value and the layer which supplied it.
+// Returns a ValueError with the failing layer if a present value can't be read.
+func (w *env_wrapper) LookupSourceE(name string) (string, Source, error) {
+	r, err := w.resolvePresent(name)
+	w.record(name, "string", nil, r.source)
+	if err != nil {
+		return "", r.source, err
+	}
+	return r.value, r.source, nil
+}
+
+// Gets a boolean value and reports if the variable is present.
+// Returns an error if a present value can't be parsed.
+func (w *env_wrapper) LookupBool(name string) (bool, bool, error) {
+	return Lookup[bool](w, name)
+}
+
+// Gets a integer value and reports if the variable is present.
+// Returns an error if a present value can't be parsed.
+func (w *env_wrapper) LookupInt(name string) (int, bool, error) {
+	return Lookup[int](w, name)
+}
+
+// Gets a boolean value and the layer which supplied it.
+// Returns an error if a present value can't be read or parsed.
+func (w *env_wrapper) LookupBoolSource(name string) (bool, Source, error) {
+	return LookupSourceOf[bool](w, name)
+}
+
+// Gets a integer value and the layer which supplied it.
+// Returns an error if a present value can't be read or parsed.
+func (w *env_wrapper) LookupIntSource(name string) (int, Source, error) {
+	return LookupSourceOf[int](w, name)
+}
+
+// Gets a string array split by the whitespace character and reports if the variable is present.
+func (w *env_wrapper) LookupStringArray(name string) ([]string, bool) {
+	return w.LookupStringArraySep(name, " ")
+}
+
+// Gets a string array split by a seperator like GetStringArraySep and reports if the variable is present.
+// A present but empty variable results in an empty array.
+// Values which can't be read are reported as absent, use LookupStringArraySepE to tell them apart.
+func (w *env_wrapper) LookupStringArraySep(name, seperator string) ([]string, bool) {
+	res, ok, err := w.LookupStringArraySepE(name, seperator)
+	if err != nil {
+		return []string{}, false
+	}
+	return res, ok
+}
+
+// Gets a string array split by a seperator and reports if the variable is present.
+// Returns a ValueError if a present value can't be read.
+func (w *env_wrapper) LookupStringArraySepE(name, seperator string) ([]string, bool, error) {
+	r, err := w.resolvePresent(name)
+	w.record(name, "[]string", nil, r.source)
+	if err != nil {
+		return []string{}, true, err
+	}
+	return splitArray(r.value, seperator), r.source != SourceNone, nil
+}
@@ -0,0 +1,50 @@
+package env_wrapper
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestLookupSourceOf(t *testing.T) {
+	w := newTestWrapper(t,
+		map[string]string{"PORT": "443", "BROKEN": "x"},
+		map[string]string{"PORT": "80", "DEBUG": "true", "EMPTY": "", "BAD": "maybe"},
+	)
+	tests := []struct {
+		name    string
+		want    int
+		wantSrc Source
+		err     bool
+	}{
+		{"PORT", 443, SourceSecret, false},
+		{"MISSING", 0, SourceNone, false},
+		{"BROKEN", 0, SourceSecret, true},
+		{"EMPTY", 0, Source("env"), true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, src, err := w.LookupIntSource(tt.name)
+			if (err != nil) != tt.err {
+				t.Fatalf("unexpected error %v", err)
+			}
+			if got != tt.want || src != tt.wantSrc {
+				t.Errorf("got %d from %q, want %d from %q", got, src, tt.want, tt.wantSrc)
+			}
+			var verr *ValueError
+			if err != nil && (!errors.As(err, &verr) || verr.Source != tt.wantSrc) {
+				t.Errorf("expected a ValueError from %q, got %v", tt.wantSrc, err)
+			}
+			_, ok, _ := w.LookupInt(tt.name)
+			if ok != (tt.wantSrc != SourceNone) {
+				t.Errorf("LookupInt reported present=%t", ok)
+			}
+		})
+	}
+
+	if res, src, err := w.LookupBoolSource("DEBUG"); err != nil || !res || src != Source("env") {
+		t.Errorf("got %t from %q, %v", res, src, err)
+	}
+	if _, src, err := w.LookupBoolSource("BAD"); err == nil || src != Source("env") {
+		t.Errorf("expected a parse error from env, got %q, %v", src, err)
+	}
+}
@@ -0,0 +1,29 @@
+package env_wrapper
+
+import (
+	"strings"
+)
+
+// Converts a secret file name without prefix and namespace into a key.
+// The result is always converted to upper case.
+type KeyMapper func(name string) string
+
+// Maps names like "db-password" to "db_password".
+func DashToUnderscore(name string) string {
+	return strings.ReplaceAll(name, "-", "_")
+}
+
+// Maps names like "db.password" to "db__password".
+func DotsToDoubleUnderscore(name string) string {
+	return strings.ReplaceAll(name, ".", "__")
+}
+
+// Creates a mapper which applies every mapper in the given order.
+func ChainMappers(mappers ...KeyMapper) KeyMapper {
+	return func(name string) string {
+		for _, m := range mappers {
+			name = m(name)
+		}
+		return name
+	}
+}
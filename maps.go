@@ -0,0 +1,104 @@
+package env_wrapper
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Gets a string map from a "k=v,k2=v2" value or returns an empty map if the variable doesn't exist or is malformed.
+func (w *env_wrapper) GetStringMap(name string) map[string]string {
+	return w.GetStringMapSep(name, ",", "=")
+}
+
+// Gets a string map by splitting the value into pairs with pairSep and pairs into key and value with kvSep.
+// Returns an empty map if the variable doesn't exist or is malformed.
+func (w *env_wrapper) GetStringMapSep(name, pairSep, kvSep string) map[string]string {
+	res, err := GetMapSepE[string](w, name, pairSep, kvSep)
+	if err != nil {
+		return map[string]string{}
+	}
+	return res
+}
+
+// Gets a string map from a "k=v,k2=v2" value.
+// Returns an error if the variable doesn't exist, a pair is malformed or a key is duplicated.
+func (w *env_wrapper) GetStringMapE(name string) (map[string]string, error) {
+	return GetMapSepE[string](w, name, ",", "=")
+}
+
+// Gets a string map by splitting the value into pairs with pairSep and pairs into key and value with kvSep.
+// Returns an error if the variable doesn't exist, a pair is malformed or a key is duplicated.
+func (w *env_wrapper) GetStringMapSepE(name, pairSep, kvSep string) (map[string]string, error) {
+	return GetMapSepE[string](w, name, pairSep, kvSep)
+}
+
+// Gets a integer map from a "k=1,k2=2" value or returns an empty map if the variable doesn't exist or is malformed.
+func (w *env_wrapper) GetIntMap(name string) map[string]int {
+	res, err := w.GetIntMapE(name)
+	if err != nil {
+		return map[string]int{}
+	}
+	return res
+}
+
+// Gets a integer map from a "k=1,k2=2" value.
+// Returns an error if the variable doesn't exist, a pair is malformed, a key is duplicated or a value can't be parsed.
+func (w *env_wrapper) GetIntMapE(name string) (map[string]int, error) {
+	return GetMapSepE[int](w, name, ",", "=")
+}
+
+// Gets a duration map from a "k=1s,k2=5m" value or returns an empty map if the variable doesn't exist or is malformed.
+func (w *env_wrapper) GetDurationMap(name string) map[string]time.Duration {
+	res, err := w.GetDurationMapE(name)
+	if err != nil {
+		return map[string]time.Duration{}
+	}
+	return res
+}
+
+// Gets a duration map from a "k=1s,k2=5m" value.
+// Returns an error if the variable doesn't exist, a pair is malformed, a key is duplicated or a value can't be parsed.
+func (w *env_wrapper) GetDurationMapE(name string) (map[string]time.Duration, error) {
+	return GetMapSepE[time.Duration](w, name, ",", "=")
+}
+
+// Gets a map with values of type V by splitting the value into pairs with pairSep and pairs into key and value with kvSep.
+// Keys and values are trimmed and empty pairs are dropped.
+// Returns an error if the variable doesn't exist, a pair is malformed, a key is duplicated or a value can't be parsed.
+func GetMapSepE[V any](w *env_wrapper, name, pairSep, kvSep string) (map[string]V, error) {
+	r, err := w.resolve(name)
+	w.record(name, typeName[map[string]V](), nil, r.source)
+	if err != nil {
+		return map[string]V{}, err
+	}
+	if r.source == SourceNone {
+		return map[string]V{}, w.valueError(name, r, ErrNotSet)
+	}
+	parser, err := parserFor[V]()
+	if err != nil {
+		return map[string]V{}, w.valueError(name, r, err)
+	}
+	res := make(map[string]V)
+	for i, pair := range splitClean(r.value, pairSep) {
+		kv := strings.SplitN(pair, kvSep, 2)
+		key := strings.TrimSpace(kv[0])
+		if len(kv) != 2 || len(key) == 0 {
+			return map[string]V{}, w.valueError(name, r, fmt.Errorf("pair %d is malformed", i))
+		}
+		if _, ok := res[key]; ok {
+			return map[string]V{}, w.valueError(name, r, fmt.Errorf("duplicate key %q", key))
+		}
+		strval := strings.TrimSpace(kv[1])
+		val, err := parser(strval)
+		if err != nil {
+			err = numErrCause(err)
+			if r.sensitive {
+				err = redactError(err, strval)
+			}
+			return map[string]V{}, w.valueError(name, r, fmt.Errorf("value of key %q: %w", key, err))
+		}
+		res[key] = val
+	}
+	return res, nil
+}
@@ -0,0 +1,55 @@
+package env_wrapper
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestGetStringMapE(t *testing.T) {
+	tests := []struct {
+		value string
+		want  map[string]string
+		err   string
+	}{
+		{"a=1", map[string]string{"a": "1"}, ""},
+		{" a = 1 , b=2,", map[string]string{"a": "1", "b": "2"}, ""},
+		{"a=x=y", map[string]string{"a": "x=y"}, ""},
+		{"a=", map[string]string{"a": ""}, ""},
+		{"a", nil, "pair 0 is malformed"},
+		{"=1", nil, "pair 0 is malformed"},
+		{"a=1,a=2", nil, `duplicate key "a"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			w := newTestWrapper(t, nil, map[string]string{"MAP": tt.value})
+			got, err := w.GetStringMapE("MAP")
+			if len(tt.err) > 0 {
+				if err == nil || !strings.Contains(err.Error(), tt.err) {
+					t.Fatalf("expected error containing %q, got %v", tt.err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestGetMapRedaction(t *testing.T) {
+	w := newTestWrapper(t, map[string]string{"TMAP": "a=5x"}, map[string]string{"PMAP": "a=5x"})
+
+	_, err := GetMapSepE[time.Duration](w, "TMAP", ",", "=")
+	if err == nil || strings.Contains(err.Error(), "5x") || !strings.Contains(err.Error(), `value of key "a"`) {
+		t.Errorf("expected a redacted error, got %v", err)
+	}
+	_, err = GetMapSepE[time.Duration](w, "PMAP", ",", "=")
+	if err == nil || !strings.Contains(err.Error(), "5x") {
+		t.Errorf("expected the plain value in the error, got %v", err)
+	}
+}
@@ -0,0 +1,130 @@
+package env_wrapper
+
+import (
+	"time"
+)
+
+// Gets a floating point value or returns 0 if the variable doesn't exist.
+func (w *env_wrapper) GetFloat64(name string) float64 {
+	return w.GetFloat64Def(name, 0)
+}
+
+// Gets a floating point value or returns a default value if variable doesn't exist.
+func (w *env_wrapper) GetFloat64Def(name string, defval float64) float64 {
+	res, err := w.GetFloat64DefE(name, defval)
+	if err != nil {
+		return defval
+	}
+	return res
+}
+
+// Gets a floating point value or returns an error if the variable doesn't exist or can't be parsed.
+func (w *env_wrapper) GetFloat64E(name string) (float64, error) {
+	return GetE[float64](w, name)
+}
+
+// Gets a floating point value or returns a default value if variable doesn't exist.
+// Returns an error if the value can't be parsed.
+func (w *env_wrapper) GetFloat64DefE(name string, defval float64) (float64, error) {
+	return GetDefE(w, name, defval)
+}
+
+// Gets a 64 bit integer value or returns 0 if the variable doesn't exist.
+func (w *env_wrapper) GetInt64(name string) int64 {
+	return w.GetInt64Def(name, 0)
+}
+
+// Gets a 64 bit integer value or returns a default value if variable doesn't exist.
+func (w *env_wrapper) GetInt64Def(name string, defval int64) int64 {
+	res, err := w.GetInt64DefE(name, defval)
+	if err != nil {
+		return defval
+	}
+	return res
+}
+
+// Gets a 64 bit integer value or returns an error if the variable doesn't exist or can't be parsed.
+func (w *env_wrapper) GetInt64E(name string) (int64, error) {
+	return GetE[int64](w, name)
+}
+
+// Gets a 64 bit integer value or returns a default value if variable doesn't exist.
+// Returns an error if the value can't be parsed.
+func (w *env_wrapper) GetInt64DefE(name string, defval int64) (int64, error) {
+	return GetDefE(w, name, defval)
+}
+
+// Gets an unsigned integer value or returns 0 if the variable doesn't exist.
+func (w *env_wrapper) GetUint(name string) uint {
+	return w.GetUintDef(name, 0)
+}
+
+// Gets an unsigned integer value or returns a default value if variable doesn't exist.
+func (w *env_wrapper) GetUintDef(name string, defval uint) uint {
+	res, err := w.GetUintDefE(name, defval)
+	if err != nil {
+		return defval
+	}
+	return res
+}
+
+// Gets an unsigned integer value or returns an error if the variable doesn't exist or can't be parsed.
+func (w *env_wrapper) GetUintE(name string) (uint, error) {
+	return GetE[uint](w, name)
+}
+
+// Gets an unsigned integer value or returns a default value if variable doesn't exist.
+// Returns an error if the value can't be parsed.
+func (w *env_wrapper) GetUintDefE(name string, defval uint) (uint, error) {
+	return GetDefE(w, name, defval)
+}
+
+// Gets a 64 bit unsigned integer value or returns 0 if the variable doesn't exist.
+func (w *env_wrapper) GetUint64(name string) uint64 {
+	return w.GetUint64Def(name, 0)
+}
+
+// Gets a 64 bit unsigned integer value or returns a default value if variable doesn't exist.
+func (w *env_wrapper) GetUint64Def(name string, defval uint64) uint64 {
+	res, err := w.GetUint64DefE(name, defval)
+	if err != nil {
+		return defval
+	}
+	return res
+}
+
+// Gets a 64 bit unsigned integer value or returns an error if the variable doesn't exist or can't be parsed.
+func (w *env_wrapper) GetUint64E(name string) (uint64, error) {
+	return GetE[uint64](w, name)
+}
+
+// Gets a 64 bit unsigned integer value or returns a default value if variable doesn't exist.
+// Returns an error if the value can't be parsed.
+func (w *env_wrapper) GetUint64DefE(name string, defval uint64) (uint64, error) {
+	return GetDefE(w, name, defval)
+}
+
+// Gets a duration value or returns 0 if the variable doesn't exist.
+func (w *env_wrapper) GetDuration(name string) time.Duration {
+	return w.GetDurationDef(name, 0)
+}
+
+// Gets a duration value or returns a default value if variable doesn't exist.
+func (w *env_wrapper) GetDurationDef(name string, defval time.Duration) time.Duration {
+	res, err := w.GetDurationDefE(name, defval)
+	if err != nil {
+		return defval
+	}
+	return res
+}
+
+// Gets a duration value or returns an error if the variable doesn't exist or can't be parsed.
+func (w *env_wrapper) GetDurationE(name string) (time.Duration, error) {
+	return GetE[time.Duration](w, name)
+}
+
+// Gets a duration value or returns a default value if variable doesn't exist.
+// Returns an error if the value can't be parsed.
+func (w *env_wrapper) GetDurationDefE(name string, defval time.Duration) (time.Duration, error) {
+	return GetDefE(w, name, defval)
+}
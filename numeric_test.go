@@ -0,0 +1,76 @@
+package env_wrapper
+
+import (
+	"errors"
+	"math"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestNumericGetters(t *testing.T) {
+	w := newTestWrapper(t, nil, map[string]string{
+		"FLOAT":      "0.25",
+		"FLOAT_EXP":  "1e3",
+		"FLOAT_OVER": "1e400",
+		"INT64":      "-9223372036854775808",
+		"INT64_OVER": "9223372036854775808",
+		"UINT":       "42",
+		"UINT_NEG":   "-1",
+		"UINT64":     "18446744073709551615",
+		"UINT_OVER":  "18446744073709551616",
+		"DURATION":   "1h30m",
+		"DUR_NOUNIT": "10",
+		"INVALID":    "x",
+	})
+	tests := []struct {
+		name string
+		get  func(string) (any, error)
+		want any
+		err  error
+	}{
+		{"FLOAT", func(n string) (any, error) { return w.GetFloat64E(n) }, 0.25, nil},
+		{"FLOAT_EXP", func(n string) (any, error) { return w.GetFloat64E(n) }, 1000.0, nil},
+		{"FLOAT_OVER", func(n string) (any, error) { return w.GetFloat64E(n) }, 0.0, strconv.ErrRange},
+		{"INT64", func(n string) (any, error) { return w.GetInt64E(n) }, int64(math.MinInt64), nil},
+		{"INT64_OVER", func(n string) (any, error) { return w.GetInt64E(n) }, int64(0), strconv.ErrRange},
+		{"INT64_OVER", func(n string) (any, error) { return w.GetIntE(n) }, 0, strconv.ErrRange},
+		{"UINT", func(n string) (any, error) { return w.GetUintE(n) }, uint(42), nil},
+		{"UINT_NEG", func(n string) (any, error) { return w.GetUintE(n) }, uint(0), strconv.ErrSyntax},
+		{"UINT64", func(n string) (any, error) { return w.GetUint64E(n) }, uint64(math.MaxUint64), nil},
+		{"UINT_OVER", func(n string) (any, error) { return w.GetUint64E(n) }, uint64(0), strconv.ErrRange},
+		{"DURATION", func(n string) (any, error) { return w.GetDurationE(n) }, 90 * time.Minute, nil},
+		{"INVALID", func(n string) (any, error) { return w.GetFloat64E(n) }, 0.0, strconv.ErrSyntax},
+		{"MISSING", func(n string) (any, error) { return w.GetDurationE(n) }, time.Duration(0), ErrNotSet},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.get(tt.name)
+			if tt.err != nil && !errors.Is(err, tt.err) {
+				t.Fatalf("expected %v, got %v", tt.err, err)
+			}
+			if tt.err == nil && err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("got %v (%T), want %v (%T)", got, got, tt.want, tt.want)
+			}
+		})
+	}
+
+	if _, err := w.GetDurationE("DUR_NOUNIT"); err == nil {
+		t.Error("expected an error for a duration without unit")
+	}
+	if got := w.GetInt64Def("INT64_OVER", 7); got != 7 {
+		t.Errorf("expected the default on overflow, got %d", got)
+	}
+	if got := w.GetUintDef("UINT_NEG", 7); got != 7 {
+		t.Errorf("expected the default for a negative value, got %d", got)
+	}
+	if got := w.GetDurationDef("MISSING", time.Second); got != time.Second {
+		t.Errorf("expected the default, got %v", got)
+	}
+	if got := w.GetFloat64("FLOAT"); got != 0.25 {
+		t.Errorf("got %v", got)
+	}
+}
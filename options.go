@@ -0,0 +1,81 @@
+package env_wrapper
+
+// Configures the providers created by New, Default and their provider constructors.
+type Option func(*options)
+
+type options struct {
+	fileMaxSize    int64
+	fileAllowedDir string
+	kubernetes     bool
+	secretPrefix   string
+	namespace      string
+	keyMapper      KeyMapper
+	strict         bool
+}
+
+// Collects the options with their default values.
+func newOptions(opts []Option) *options {
+	res := &options{
+		secretPrefix: "ENV_",
+	}
+	for _, opt := range opts {
+		opt(res)
+	}
+	return res
+}
+
+// Limits the size of files referenced by "_FILE" variables.
+// A limit of 0 disables the check.
+func FileMaxSize(size int64) Option {
+	return func(o *options) {
+		o.fileMaxSize = size
+	}
+}
+
+// Rejects files referenced by "_FILE" variables outside of a directory.
+func FileAllowedDir(dir string) Option {
+	return func(o *options) {
+		o.fileAllowedDir = dir
+	}
+}
+
+// Reads the secret directory in the layout of a kubernetes secret volume.
+// File names are used without prefix and mapped like "db-password" to "DB_PASSWORD".
+// Later prefix and mapper options take precedence.
+func KubernetesLayout() Option {
+	return func(o *options) {
+		o.kubernetes = true
+		o.secretPrefix = ""
+		o.keyMapper = DashToUnderscore
+	}
+}
+
+// Sets the prefix every secret file needs, an empty prefix accepts every file.
+// The prefix is case sensitive and defaults to "ENV_".
+func SecretPrefix(prefix string) Option {
+	return func(o *options) {
+		o.secretPrefix = prefix
+	}
+}
+
+// Only reads secret files whose name continues with the namespace and an underscore after the prefix.
+// The namespace is matched case insensitive and removed from the key.
+func Namespace(namespace string) Option {
+	return func(o *options) {
+		o.namespace = namespace
+	}
+}
+
+// Sets the mapper which converts secret file names into keys.
+func MapKeys(mapper KeyMapper) Option {
+	return func(o *options) {
+		o.keyMapper = mapper
+	}
+}
+
+// Lets NewE fail if the secret directory doesn't exist.
+func Strict() Option {
+	return func(o *options) {
+		o.strict = true
+	}
+}
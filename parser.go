@@ -0,0 +1,115 @@
+package env_wrapper
+
+import (
+	"encoding"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Returned if no parser is available for a type.
+var ErrNoParser = errors.New("no parser registered for type")
+
+// Parser of a registered type.
+type parserEntry struct {
+	typed any
+	set   func(strval string, fv reflect.Value) error
+}
+
+var (
+	parsersMu sync.RWMutex
+	// Parsers registered with RegisterParser, they take precedence over the builtin ones.
+	parsers = map[reflect.Type]parserEntry{}
+	// Parsers of the types supported by the typed getters.
+	builtinParsers = map[reflect.Type]parserEntry{}
+)
+
+func init() {
+	addParser(builtinParsers, func(strval string) (string, error) {
+		return strval, nil
+	})
+	addParser(builtinParsers, strconv.ParseBool)
+	addParser(builtinParsers, func(strval string) (int, error) {
+		res, err := strconv.ParseInt(strval, 10, strconv.IntSize)
+		return int(res), err
+	})
+	addParser(builtinParsers, func(strval string) (int64, error) {
+		return strconv.ParseInt(strval, 10, 64)
+	})
+	addParser(builtinParsers, func(strval string) (uint, error) {
+		res, err := strconv.ParseUint(strval, 10, strconv.IntSize)
+		return uint(res), err
+	})
+	addParser(builtinParsers, func(strval string) (uint64, error) {
+		return strconv.ParseUint(strval, 10, 64)
+	})
+	addParser(builtinParsers, func(strval string) (float64, error) {
+		return strconv.ParseFloat(strval, 64)
+	})
+	addParser(builtinParsers, time.ParseDuration)
+	addParser(builtinParsers, func(strval string) ([]string, error) {
+		return SplitList(strval, " ")
+	})
+	addParser(builtinParsers, func(strval string) (Secret, error) {
+		return NewSecret(strval), nil
+	})
+}
+
+// Registers a parser which is used by Get, the typed getters and Unmarshal for values of type T.
+// Registering a parser for a builtin type replaces the builtin parser.
+func RegisterParser[T any](parser func(strval string) (T, error)) {
+	parsersMu.Lock()
+	defer parsersMu.Unlock()
+
+	addParser(parsers, parser)
+}
+
+// Stores a typed parser and its reflection based counterpart.
+func addParser[T any](target map[reflect.Type]parserEntry, parser func(strval string) (T, error)) {
+	target[reflect.TypeOf((*T)(nil)).Elem()] = parserEntry{
+		typed: parser,
+		set: func(strval string, fv reflect.Value) error {
+			res, err := parser(strval)
+			if err != nil {
+				return err
+			}
+			fv.Set(reflect.ValueOf(&res).Elem())
+			return nil
+		},
+	}
+}
+
+// Gets the parser of T.
+// Registered parsers are preferred over builtin parsers and types implementing encoding.TextUnmarshaler.
+func parserFor[T any]() (func(strval string) (T, error), error) {
+	t := reflect.TypeOf((*T)(nil)).Elem()
+	if entry, ok := registeredParser(t); ok {
+		return entry.typed.(func(string) (T, error)), nil
+	}
+	if entry, ok := builtinParsers[t]; ok {
+		return entry.typed.(func(string) (T, error)), nil
+	}
+	if reflect.PtrTo(t).Implements(textUnmarshalerType) {
+		return func(strval string) (T, error) {
+			var res T
+			err := any(&res).(encoding.TextUnmarshaler).UnmarshalText([]byte(strval))
+			return res, err
+		}, nil
+	}
+	return nil, fmt.Errorf("%w %s", ErrNoParser, t)
+}
+
+// Gets a parser registered with RegisterParser.
+func registeredParser(t reflect.Type) (parserEntry, bool) {
+	parsersMu.RLock()
+	defer parsersMu.RUnlock()
+
+	entry, ok := parsers[t]
+	return entry, ok
+}
+
+// Type of encoding.TextUnmarshaler which is used for types without registered parser.
+var textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
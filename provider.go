@@ -0,0 +1,107 @@
+package env_wrapper
+
+import (
+	"os"
+	"strings"
+)
+
+// Source of variables which can be chained with NewChain.
+// Keys passed to Lookup are always upper case.
+type Provider interface {
+	// Name of the layer which is reported as Source.
+	Name() string
+	// Gets a value and reports if the key is present.
+	Lookup(key string) (string, bool)
+}
+
+// Implemented by providers whose values must never be printed.
+type SensitiveProvider interface {
+	Provider
+	Sensitive() bool
+}
+
+// Implemented by providers which can list their keys.
+type EnumeratingProvider interface {
+	Provider
+	Keys() []string
+}
+
+// Implemented by providers which can fail to read a present value,
+// for example a file which is too large or outside of the allowed directory.
+type CheckedProvider interface {
+	Provider
+	// Gets a value, reports if the key is present and why a present value couldn't be read.
+	LookupE(key string) (string, bool, error)
+}
+
+// Implemented by providers which can tell where a value is stored,
+// for example a file path or the name of an environment variable.
+type LocatingProvider interface {
+	Provider
+	Location(key string) string
+}
+
+type envProvider struct{}
+
+// Creates a provider which reads the process environment.
+func NewEnvProvider() *envProvider {
+	return &envProvider{}
+}
+
+func (p *envProvider) Name() string {
+	return string(SourceEnv)
+}
+
+func (p *envProvider) Lookup(key string) (string, bool) {
+	res, ok := os.LookupEnv(key)
+	return strings.TrimSpace(res), ok
+}
+
+func (p *envProvider) Location(key string) string {
+	return key
+}
+
+func (p *envProvider) Keys() []string {
+	res := []string{}
+	for _, kv := range os.Environ() {
+		if i := strings.IndexByte(kv, '='); i > 0 {
+			res = append(res, strings.ToUpper(kv[:i]))
+		}
+	}
+	return res
+}
+
+type mapProvider struct {
+	name   string
+	values map[string]string
+}
+
+// Creates a provider which serves values from memory.
+// Keys are converted to upper case.
+func NewMapProvider(name string, values map[string]string) *mapProvider {
+	res := &mapProvider{
+		name,
+		make(map[string]string, len(values)),
+	}
+	for k, v := range values {
+		res.values[strings.ToUpper(k)] = strings.TrimSpace(v)
+	}
+	return res
+}
+
+func (p *mapProvider) Name() string {
+	return p.name
+}
+
+func (p *mapProvider) Lookup(key string) (string, bool) {
+	res, ok := p.values[key]
+	return res, ok
+}
+
+func (p *mapProvider) Keys() []string {
+	res := make([]string, 0, len(p.values))
+	for key := range p.values {
+		res = append(res, key)
+	}
+	return res
+}
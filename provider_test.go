@@ -0,0 +1,92 @@
+package env_wrapper
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// Provider counting its lookups.
+type countingProvider struct {
+	values map[string]string
+	calls  int
+}
+
+func (p *countingProvider) Name() string {
+	return "counting"
+}
+
+func (p *countingProvider) Lookup(key string) (string, bool) {
+	p.calls++
+	res, ok := p.values[key]
+	return res, ok
+}
+
+func TestChainOrder(t *testing.T) {
+	first := NewMapProvider("first", map[string]string{"A": "1", "EMPTY": ""})
+	second := NewMapProvider("second", map[string]string{"A": "2", "B": "2", "EMPTY": "2"})
+	last := &countingProvider{values: map[string]string{"C": "3"}}
+	tests := []struct {
+		name   string
+		chain  []Provider
+		want   string
+		source Source
+	}{
+		{"A", []Provider{first, second, last}, "1", "first"},
+		{"A", []Provider{second, first, last}, "2", "second"},
+		{"B", []Provider{first, second, last}, "2", "second"},
+		{"EMPTY", []Provider{first, second, last}, "", "first"},
+		{"c", []Provider{first, second, last}, "3", "counting"},
+		{"MISSING", []Provider{first, second, last}, "", SourceNone},
+		{"A", nil, "", SourceNone},
+	}
+	for _, tt := range tests {
+		w := NewChain(tt.chain...)
+		got, src := w.LookupSource(tt.name)
+		if got != tt.want || src != tt.source {
+			t.Errorf("%s: got %q from %q, want %q from %q", tt.name, got, src, tt.want, tt.source)
+		}
+	}
+
+	if got := NewChain(first, second).GetString("EMPTY"); got != "2" {
+		t.Errorf("expected empty values to fall through, got %q", got)
+	}
+	last.calls = 0
+	NewChain(first, last).GetString("A")
+	if last.calls != 0 {
+		t.Errorf("expected the chain to stop at the first value, got %d lookups", last.calls)
+	}
+}
+
+func TestDefaultChain(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "ENV_CHAIN_A"), []byte("secret\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "ENV_CHAIN_EMPTY"), nil, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("CHAIN_A", "env")
+	t.Setenv("CHAIN_B", "env")
+	t.Setenv("CHAIN_EMPTY", "env")
+	w := New(dir)
+	tests := []struct {
+		name   string
+		want   string
+		source Source
+	}{
+		{"CHAIN_A", "secret", SourceSecret},
+		{"chain_b", "env", SourceEnv},
+		{"CHAIN_EMPTY", "", SourceSecret},
+		{"CHAIN_MISSING", "", SourceNone},
+	}
+	for _, tt := range tests {
+		got, src := w.LookupSource(tt.name)
+		if got != tt.want || src != tt.source {
+			t.Errorf("%s: got %q from %q, want %q from %q", tt.name, got, src, tt.want, tt.source)
+		}
+	}
+	if got := w.GetString("CHAIN_EMPTY"); got != "env" {
+		t.Errorf("expected an empty secret to fall through, got %q", got)
+	}
+}
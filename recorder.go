@@ -0,0 +1,113 @@
+package env_wrapper
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// Entry of the access inventory, one per key and getter type.
+type AccessRecord struct {
+	Key     string  `json:"key"`
+	Type    string  `json:"type"`
+	Default *string `json:"default,omitempty"`
+	Found   bool    `json:"found"`
+	Source  Source  `json:"source,omitempty"`
+	Count   int     `json:"count"`
+}
+
+// Inventory of every key read through the getters after recording was enabled.
+type Recorder struct {
+	mu      sync.Mutex
+	records map[string]*AccessRecord
+	order   []string
+}
+
+// Enables recording of every getter access and returns the inventory.
+// Repeated calls return the same recorder.
+func (w *env_wrapper) Record() *Recorder {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.recorder == nil {
+		w.recorder = &Recorder{
+			records: make(map[string]*AccessRecord),
+		}
+	}
+	return w.recorder
+}
+
+// Adds an access to the inventory if recording is enabled.
+// Defaults of keys read as Secret are redacted.
+func (w *env_wrapper) record(name, typ string, defval *string, src Source) {
+	w.mu.Lock()
+	r := w.recorder
+	w.mu.Unlock()
+	if r == nil {
+		return
+	}
+	if defval != nil && w.readAsSecret(w.key(name)) {
+		defstr := redacted
+		defval = &defstr
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	upname := w.key(name)
+	id := upname + " " + typ
+	rec, ok := r.records[id]
+	if !ok {
+		rec = &AccessRecord{
+			Key:  upname,
+			Type: typ,
+		}
+		r.records[id] = rec
+		r.order = append(r.order, id)
+	}
+	if defval != nil {
+		rec.Default = defval
+	}
+	rec.Found = src != SourceNone
+	rec.Source = src
+	rec.Count++
+}
+
+// Gets the name of T as used in the inventory.
+func typeName[T any]() string {
+	return reflect.TypeOf((*T)(nil)).Elem().String()
+}
+
+// Gets a copy of every record in the order of the first access.
+func (r *Recorder) Records() []AccessRecord {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	res := make([]AccessRecord, len(r.order))
+	for i, id := range r.order {
+		res[i] = *r.records[id]
+	}
+	return res
+}
+
+// Exports the inventory as JSON array.
+func (r *Recorder) JSON() ([]byte, error) {
+	return json.MarshalIndent(r.Records(), "", "  ")
+}
+
+// Exports the inventory as Markdown table.
+func (r *Recorder) Markdown() string {
+	var sb strings.Builder
+	sb.WriteString("| Key | Type | Default | Found | Source |\n")
+	sb.WriteString("| --- | --- | --- | --- | --- |\n")
+	for _, rec := range r.Records() {
+		defval := ""
+		if rec.Default != nil {
+			defval = fmt.Sprintf("`%s`", *rec.Default)
+		}
+		fmt.Fprintf(&sb, "| `%s` | %s | %s | %t | %s |\n", rec.Key, rec.Type, defval, rec.Found, rec.Source)
+	}
+	return sb.String()
+}
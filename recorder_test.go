@@ -0,0 +1,61 @@
+package env_wrapper
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestRecorder(t *testing.T) {
+	w := newTestWrapper(t, map[string]string{"TOKEN": "t"}, map[string]string{"HOST": "db", "PORTS": "1 2"})
+	w.GetString("BEFORE")
+	r := w.Record()
+	if r != w.Record() {
+		t.Fatal("expected repeated calls to return the same recorder")
+	}
+	w.GetStringDef("host", "localhost")
+	w.GetIntDef("PORT", 80)
+	w.GetString("HOST")
+	w.GetStringArray("PORTS")
+	w.GetString("token")
+	w.Sub("db").GetIntDef("port", 5432)
+	w.GetStringDef("HOST", "other")
+
+	def := func(s string) *string { return &s }
+	want := []AccessRecord{
+		{Key: "HOST", Type: "string", Default: def("other"), Found: true, Source: "env", Count: 3},
+		{Key: "PORT", Type: "int", Default: def("80"), Count: 1},
+		{Key: "PORTS", Type: "[]string", Found: true, Source: "env", Count: 1},
+		{Key: "TOKEN", Type: "string", Default: def(""), Found: true, Source: SourceSecret, Count: 1},
+		{Key: "DB_PORT", Type: "int", Default: def("5432"), Count: 1},
+	}
+	if got := r.Records(); !reflect.DeepEqual(got, want) {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+
+	bval, err := r.JSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded []map[string]any
+	if err := json.Unmarshal(bval, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if len(decoded) != len(want) || decoded[1]["default"] != "80" || decoded[1]["found"] != false || decoded[2]["default"] != nil {
+		t.Errorf("unexpected JSON %s", bval)
+	}
+	if _, ok := decoded[1]["source"]; ok {
+		t.Errorf("expected the source of a missing key to be omitted: %s", bval)
+	}
+
+	wantMarkdown := "| Key | Type | Default | Found | Source |\n" +
+		"| --- | --- | --- | --- | --- |\n" +
+		"| `HOST` | string | `other` | true | env |\n" +
+		"| `PORT` | int | `80` | false |  |\n" +
+		"| `PORTS` | []string |  | true | env |\n" +
+		"| `TOKEN` | string | `` | true | secret |\n" +
+		"| `DB_PORT` | int | `5432` | false |  |\n"
+	if got := r.Markdown(); got != wantMarkdown {
+		t.Errorf("got\n%s\nwant\n%s", got, wantMarkdown)
+	}
+}
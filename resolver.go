@@ -0,0 +1,164 @@
+package env_wrapper
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Returned if an exec reference runs a command which isn't allowed.
+var ErrExecNotAllowed = errors.New("command is not allowed")
+
+// Maximum runtime of a command run by an exec reference.
+const execTimeout = 10 * time.Second
+
+// Resolves the part of a "scheme:ref" value after the scheme.
+type Resolver func(ref string) (string, error)
+
+var (
+	resolversMu sync.RWMutex
+	// Resolvers registered with RegisterResolver, they take precedence over the builtin ones.
+	resolvers = map[string]Resolver{}
+	// Resolvers of the schemes file, env, base64, hex and exec, they depend on the options of the wrapper.
+	builtinResolvers = map[string]func(w *env_wrapper, ref string) (string, error){
+		"file":   (*env_wrapper).resolveFile,
+		"env":    (*env_wrapper).resolveEnv,
+		"base64": resolveBase64,
+		"hex":    resolveHex,
+		"exec":   (*env_wrapper).resolveExec,
+	}
+)
+
+// Registers a resolver for values like "scheme:ref".
+// Registering a builtin scheme replaces the builtin resolver.
+func RegisterResolver(scheme string, resolver Resolver) {
+	resolversMu.Lock()
+	defer resolversMu.Unlock()
+
+	resolvers[strings.ToLower(scheme)] = resolver
+}
+
+// Turns on resolving values like "file:/mnt/keys/api" or "base64:MIIB..." for every getter, Lookup, Check
+// and Unmarshal. References are resolved after expansion and only once.
+// Values with an unknown scheme like "postgres://" are used as they are.
+// Resolved values are redacted in errors.
+// File references follow FileMaxSize and FileAllowedDir, by default those passed to New.
+func (w *env_wrapper) EnableResolvers(opts ...Option) *env_wrapper {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.resolving = true
+	if len(opts) > 0 {
+		w.files = NewFileEnvProvider(opts...)
+	}
+	return w
+}
+
+// Allows "exec:command args" references to run the listed commands.
+// Commands are matched exactly with the first word of the reference and run without a shell.
+// The exec scheme is disabled as long as no command is allowed.
+func (w *env_wrapper) AllowExec(commands ...string) *env_wrapper {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.execAllowed == nil {
+		w.execAllowed = make(map[string]bool)
+	}
+	for _, cmd := range commands {
+		w.execAllowed[cmd] = true
+	}
+	return w
+}
+
+// Resolves a reference and reports if its scheme is known.
+func (w *env_wrapper) resolveRef(strval string) (string, bool, error) {
+	scheme, ref, ok := strings.Cut(strval, ":")
+	if !ok {
+		return strval, false, nil
+	}
+	scheme = strings.ToLower(scheme)
+	resolversMu.RLock()
+	resolver, ok := resolvers[scheme]
+	resolversMu.RUnlock()
+	if !ok {
+		builtin, ok := builtinResolvers[scheme]
+		if !ok {
+			return strval, false, nil
+		}
+		resolver = func(ref string) (string, error) {
+			return builtin(w, ref)
+		}
+	}
+	res, err := resolver(ref)
+	if err != nil {
+		return "", true, fmt.Errorf("resolving %s reference: %w", scheme, err)
+	}
+	return res, true, nil
+}
+
+// Reads a file with the limits of the "_FILE" variables.
+func (w *env_wrapper) resolveFile(ref string) (string, error) {
+	w.mu.Lock()
+	files := w.files
+	w.mu.Unlock()
+	bval, err := files.read(strings.TrimSpace(ref))
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(bval)), nil
+}
+
+// Gets the first non empty value of another key from the providers of the wrapper.
+func (w *env_wrapper) resolveEnv(ref string) (string, error) {
+	key := strings.ToUpper(strings.TrimSpace(ref))
+	res, src, err := w.get(key)
+	if err != nil {
+		return "", errors.Unwrap(err)
+	}
+	if src == SourceNone {
+		return "", fmt.Errorf("%s: %w", key, ErrNotSet)
+	}
+	return res, nil
+}
+
+// Decodes padded and unpadded standard base64.
+func resolveBase64(w *env_wrapper, ref string) (string, error) {
+	ref = strings.TrimSpace(ref)
+	bval, err := base64.StdEncoding.DecodeString(ref)
+	if err != nil {
+		bval, err = base64.RawStdEncoding.DecodeString(ref)
+	}
+	return string(bval), err
+}
+
+func resolveHex(w *env_wrapper, ref string) (string, error) {
+	bval, err := hex.DecodeString(strings.TrimSpace(ref))
+	return string(bval), err
+}
+
+// Runs an allowed command and returns its output without the trailing line break.
+func (w *env_wrapper) resolveExec(ref string) (string, error) {
+	args := strings.Fields(ref)
+	if len(args) == 0 {
+		return "", errors.New("empty command")
+	}
+	w.mu.Lock()
+	allowed := w.execAllowed[args[0]]
+	w.mu.Unlock()
+	if !allowed {
+		return "", fmt.Errorf("%s: %w", args[0], ErrExecNotAllowed)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), execTimeout)
+	defer cancel()
+	bval, err := exec.CommandContext(ctx, args[0], args[1:]...).Output()
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", args[0], err)
+	}
+	return strings.TrimRight(string(bval), "\r\n"), nil
+}
@@ -0,0 +1,132 @@
+package env_wrapper
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestResolvers(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "key"), []byte("filesecret\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	RegisterResolver("TEST-UPPER", func(ref string) (string, error) {
+		return strings.ToUpper(ref), nil
+	})
+	tests := []struct {
+		value string
+		want  string
+		err   string
+	}{
+		{"plain", "plain", ""},
+		{"postgres://db", "postgres://db", ""},
+		{"unknown:value", "unknown:value", ""},
+		{"file:" + filepath.Join(dir, "key"), "filesecret", ""},
+		{"FILE:" + filepath.Join(dir, "key"), "filesecret", ""},
+		{"file:" + filepath.Join(dir, "missing"), "", "resolving file reference"},
+		{"env:OTHER", "other value", ""},
+		{"env:other", "other value", ""},
+		{"env:MISSING", "", "MISSING: variable is not set"},
+		{"base64:aGVsbG8=", "hello", ""},
+		{"base64:aGVsbG8", "hello", ""},
+		{"base64:!", "", "resolving base64 reference"},
+		{"hex:3432", "42", ""},
+		{"hex:zz", "", "resolving hex reference"},
+		{"exec:echo hi", "", "echo: command is not allowed"},
+		{"test-upper:abc", "ABC", ""},
+	}
+	w := newTestWrapper(t, nil, map[string]string{"OTHER": "  other value  "}).EnableResolvers()
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, resolved, err := w.resolveRef(tt.value)
+			if len(tt.err) > 0 {
+				if err == nil || !strings.Contains(err.Error(), tt.err) {
+					t.Fatalf("expected error containing %q, got %v", tt.err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Fatalf("expected %q, got %q", tt.want, got)
+			}
+			if resolved != (got != tt.value) {
+				t.Fatalf("unexpected resolved flag %v", resolved)
+			}
+		})
+	}
+}
+
+func TestResolveFileLimits(t *testing.T) {
+	dir := t.TempDir()
+	allowed := filepath.Join(dir, "allowed")
+	if err := os.Mkdir(allowed, 0o700); err != nil {
+		t.Fatal(err)
+	}
+	outside := filepath.Join(dir, "outside.txt")
+	large := filepath.Join(allowed, "large.txt")
+	for path, value := range map[string]string{outside: "topsecret", large: "0123456789abcdef"} {
+		if err := os.WriteFile(path, []byte(value), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	env := map[string]string{"OUTSIDE": "file:" + outside, "LARGE": "file:" + large}
+	files := NewFileEnvProvider(FileAllowedDir(allowed), FileMaxSize(8))
+
+	w := NewChain(NewMapProvider("env", env), files).EnableResolvers()
+	if _, err := w.GetStringE("OUTSIDE"); !errors.Is(err, ErrOutsideDir) {
+		t.Errorf("expected ErrOutsideDir, got %v", err)
+	}
+	if _, err := w.GetStringE("LARGE"); !errors.Is(err, ErrFileTooLarge) {
+		t.Errorf("expected ErrFileTooLarge, got %v", err)
+	}
+
+	w = NewChain(NewMapProvider("env", env)).EnableResolvers(FileAllowedDir(allowed))
+	if _, err := w.GetStringE("OUTSIDE"); !errors.Is(err, ErrOutsideDir) {
+		t.Errorf("expected ErrOutsideDir, got %v", err)
+	}
+}
+
+func TestResolveReferences(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "password")
+	if err := os.WriteFile(path, []byte("hunter2"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	w := newTestWrapper(t, nil, map[string]string{
+		"PASSWORD": "file:" + path,
+		"URL":      "postgres://u:${PASSWORD}@h",
+		"PORT":     "${PASSWORD}",
+	}).EnableExpansion().EnableResolvers()
+
+	if got := w.GetString("URL"); got != "postgres://u:hunter2@h" {
+		t.Fatalf("expected the resolved reference, got %q", got)
+	}
+	_, err := w.GetIntE("PORT")
+	if err == nil || strings.Contains(err.Error(), "hunter2") {
+		t.Fatalf("expected a redacted error, got %v", err)
+	}
+	if e := w.Explain("URL"); strings.Contains(e.String(), "hunter2") {
+		t.Fatalf("expected no resolved value in the explanation, got %s", e)
+	}
+}
+
+func TestResolveExec(t *testing.T) {
+	echo, err := exec.LookPath("echo")
+	if err != nil {
+		t.Skip("echo not available")
+	}
+	w := newTestWrapper(t, nil, map[string]string{"CMD": "exec:" + echo + " hi there"}).EnableResolvers()
+	if _, err := w.GetStringE("CMD"); !errors.Is(err, ErrExecNotAllowed) {
+		t.Fatalf("expected ErrExecNotAllowed, got %v", err)
+	}
+	w.AllowExec(echo)
+	if got := w.GetString("CMD"); got != "hi there" {
+		t.Fatalf("expected the command output, got %q", got)
+	}
+}
@@ -0,0 +1,66 @@
+package env_wrapper
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+)
+
+// Holds a value which is redacted whenever it is printed, logged or encoded.
+// The raw value is only accessible with Reveal.
+// It is stored behind a pointer as fmt prints unexported fields of enclosing structs without calling Format.
+type Secret struct {
+	value *string
+}
+
+// Creates a Secret from a raw value.
+func NewSecret(value string) Secret {
+	return Secret{&value}
+}
+
+// Gets a secret value or an empty secret if the variable doesn't exist.
+// Keys read as Secret are redacted in errors, Explain and Report whatever layer supplied them.
+func (w *env_wrapper) GetSecret(name string) Secret {
+	return Get(w, name, Secret{})
+}
+
+// Gets a secret value or returns an error if the variable doesn't exist.
+func (w *env_wrapper) GetSecretE(name string) (Secret, error) {
+	return GetE[Secret](w, name)
+}
+
+// Remembers keys read into a Secret or *Secret.
+func (w *env_wrapper) trackSecretType(name string, t reflect.Type) {
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t == secretType {
+		w.trackSecret(name)
+	}
+}
+
+// Gets the raw value, the zero Secret is empty.
+func (s Secret) Reveal() string {
+	if s.value == nil {
+		return ""
+	}
+	return *s.value
+}
+
+func (s Secret) String() string {
+	return redacted
+}
+
+func (s Secret) GoString() string {
+	return redacted
+}
+
+// Prints the placeholder for every verb and flag.
+func (s Secret) Format(f fmt.State, verb rune) {
+	io.WriteString(f, redacted)
+}
+
+func (s Secret) MarshalJSON() ([]byte, error) {
+	return json.Marshal(redacted)
+}
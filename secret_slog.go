@@ -0,0 +1,13 @@
+//go:build go1.21
+// +build go1.21
+
+package env_wrapper
+
+import (
+	"log/slog"
+)
+
+// Logs the placeholder instead of the raw value.
+func (s Secret) LogValue() slog.Value {
+	return slog.StringValue(redacted)
+}
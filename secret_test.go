@@ -0,0 +1,83 @@
+package env_wrapper
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestSecretRedaction(t *testing.T) {
+	type config struct {
+		Exported Secret
+		hidden   Secret
+		ptr      *Secret
+	}
+	s := NewSecret("hunter2")
+	cfg := config{s, s, &s}
+	for _, format := range []string{"%v", "%+v", "%#v", "%s", "%q", "%x", "%d"} {
+		for _, val := range []any{s, &s, cfg, &cfg} {
+			if got := fmt.Sprintf(format, val); strings.Contains(got, "hunter2") || strings.Contains(got, fmt.Sprintf("%x", "hunter2")) {
+				t.Errorf("%s of %T leaks the value: %s", format, val, got)
+			}
+		}
+	}
+	bval, err := json.Marshal(cfg)
+	if err != nil || strings.Contains(string(bval), "hunter2") {
+		t.Errorf("JSON leaks the value: %s, %v", bval, err)
+	}
+	if s.Reveal() != "hunter2" {
+		t.Errorf("expected the raw value, got %q", s.Reveal())
+	}
+	if (Secret{}).Reveal() != "" {
+		t.Error("expected an empty zero Secret")
+	}
+}
+
+func TestSecretTypeRedaction(t *testing.T) {
+	w := newTestWrapper(t, nil, map[string]string{"PW": "hunter2", "TOKEN": "tok3n"})
+	w.Record()
+	var cfg struct {
+		Password Secret  `env:"PW" minlen:"12"`
+		Token    *Secret `env:"TOKEN" default:"deftoken"`
+		Missing  Secret  `env:"MISSING" default:"defsecret"`
+	}
+	err := w.Unmarshal(&cfg)
+	if err == nil || strings.Contains(err.Error(), "hunter2") || !strings.Contains(err.Error(), "at least 12") {
+		t.Errorf("expected a redacted validation error, got %v", err)
+	}
+	if err := w.Validate(); err == nil || strings.Contains(err.Error(), "hunter2") {
+		t.Errorf("expected a redacted violation, got %v", err)
+	}
+	if cfg.Token == nil || cfg.Token.Reveal() != "tok3n" || cfg.Missing.Reveal() != "defsecret" {
+		t.Errorf("unexpected bound secrets %q, %q", cfg.Token.Reveal(), cfg.Missing.Reveal())
+	}
+	for _, leak := range []string{"hunter2", "tok3n", "defsecret"} {
+		if report := w.Report(); strings.Contains(report, leak) {
+			t.Errorf("Report leaks %q: %s", leak, report)
+		}
+		if inventory := w.Record().Markdown(); strings.Contains(inventory, leak) {
+			t.Errorf("inventory leaks %q: %s", leak, inventory)
+		}
+	}
+}
+
+func TestGetSecretExplain(t *testing.T) {
+	w := newTestWrapper(t, nil, map[string]string{"PW": "hunter2"})
+	if got := w.GetSecret("PW").Reveal(); got != "hunter2" {
+		t.Fatalf("expected the raw value, got %q", got)
+	}
+	if e := w.Explain("PW"); e.Value != redacted || e.Source != SourceEnv || strings.Contains(e.String(), "hunter2") {
+		t.Errorf("expected a redacted explanation, got %s", e)
+	}
+	if report := w.Report(); strings.Contains(report, "hunter2") {
+		t.Errorf("Report leaks the value: %s", report)
+	}
+	if _, err := GetE[int](w, "PW"); err == nil || strings.Contains(err.Error(), "hunter2") {
+		t.Errorf("expected a redacted error, got %v", err)
+	}
+	if _, err := w.GetSecretE("MISSING"); !errors.Is(err, ErrNotSet) {
+		t.Errorf("expected ErrNotSet, got %v", err)
+	}
+}
@@ -0,0 +1,200 @@
+package env_wrapper
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+type secretsProvider struct {
+	path       string
+	prefix     string
+	namespace  string
+	mapKey     KeyMapper
+	kubernetes bool
+	strict     bool
+	snapshot   atomic.Value
+	mu         sync.Mutex
+	listeners  []func(key, old, new string)
+	stop       chan struct{}
+}
+
+// Creates a provider which reads every "ENV_" prefixed file of a secret directory.
+// The prefix, namespace and key mapping can be changed with options.
+// The directory is read once unless Watch is called.
+// Unreadable entries are skipped silently.
+func NewSecretsProvider(secretsDir string, opts ...Option) *secretsProvider {
+	res, _ := NewSecretsProviderE(secretsDir, opts...)
+	return res
+}
+
+// Creates a secret provider like NewSecretsProvider.
+// Returns a ScanError listing every entry which couldn't be read.
+// The provider is usable with the readable secrets even if an error is returned.
+func NewSecretsProviderE(secretsDir string, opts ...Option) (*secretsProvider, error) {
+	o := newOptions(opts)
+	res := &secretsProvider{
+		path:       strings.TrimRight(strings.TrimSpace(secretsDir), "\\//"),
+		prefix:     o.secretPrefix,
+		namespace:  strings.ToUpper(o.namespace),
+		mapKey:     o.keyMapper,
+		kubernetes: o.kubernetes,
+		strict:     o.strict,
+	}
+	snapshot, err := res.scan()
+	res.snapshot.Store(snapshot)
+	return res, err
+}
+
+// Maps a file name to a key and reports if the file belongs to the namespace.
+func (p *secretsProvider) key(filename string) (string, bool) {
+	name := strings.TrimPrefix(filename, p.prefix)
+	if len(p.namespace) > 0 {
+		nsprefix := p.namespace + "_"
+		if !strings.HasPrefix(strings.ToUpper(name), nsprefix) {
+			return "", false
+		}
+		name = name[len(nsprefix):]
+	}
+	if p.mapKey != nil {
+		name = p.mapKey(name)
+	}
+	return strings.ToUpper(name), len(name) > 0
+}
+
+// Secrets and their file paths read by a single scan.
+type secretSnapshot struct {
+	envSecrets map[string]string
+	paths      map[string]string
+	// Keys of entries which couldn't be read.
+	failed map[string]bool
+	// The directory is missing or couldn't be listed.
+	unlisted bool
+}
+
+// Reads the secret directory into a new snapshot.
+// Returns a ScanError listing every entry which couldn't be read.
+func (p *secretsProvider) scan() (*secretSnapshot, error) {
+	res := &secretSnapshot{
+		envSecrets: make(map[string]string),
+		paths:      make(map[string]string),
+		failed:     make(map[string]bool),
+	}
+	var errs []error
+	if _, ferr := os.Stat(p.path); !os.IsNotExist(ferr) {
+
+		files, ferr := ioutil.ReadDir(p.path)
+		if ferr == nil {
+			for _, file := range files {
+				keyname, ok := p.key(file.Name())
+				if !ok || !strings.HasPrefix(file.Name(), p.prefix) {
+					continue
+				}
+				isFile, ferr := p.isFile(file)
+				if ferr != nil {
+					errs = append(errs, ferr)
+					res.failed[keyname] = true
+				}
+				if isFile {
+					fpath := p.path + "/" + file.Name()
+					bval, eerr := ioutil.ReadFile(fpath)
+					if eerr == nil {
+						sval := strings.TrimSpace(string(bval))
+						res.envSecrets[keyname] = sval
+						res.paths[keyname] = fpath
+					} else {
+						errs = append(errs, eerr)
+						res.failed[keyname] = true
+					}
+				}
+			}
+		} else {
+			errs = append(errs, ferr)
+			res.unlisted = true
+		}
+	} else {
+		res.unlisted = true
+		if p.strict {
+			errs = append(errs, ferr)
+		}
+	}
+	if len(errs) > 0 {
+		return res, &ScanError{p.path, errs}
+	}
+	return res, nil
+}
+
+// Keeps the previous values of the entries which couldn't be read.
+// The previous snapshot is kept entirely if the directory is missing or couldn't be listed.
+func (s *secretSnapshot) keep(old *secretSnapshot) *secretSnapshot {
+	if s.unlisted {
+		return old
+	}
+	for key := range s.failed {
+		if sval, ok := old.envSecrets[key]; ok {
+			s.envSecrets[key] = sval
+			s.paths[key] = old.paths[key]
+		}
+	}
+	return s
+}
+
+// Reports if a directory entry is a readable secret file.
+// In kubernetes mode hidden ".." entries are skipped and symlinks are
+// only followed if they resolve to a file inside the secret directory.
+func (p *secretsProvider) isFile(file os.FileInfo) (bool, error) {
+	if !p.kubernetes {
+		return !file.IsDir(), nil
+	}
+	if strings.HasPrefix(file.Name(), "..") {
+		return false, nil
+	}
+	path := filepath.Join(p.path, file.Name())
+	resolved, err := filepath.EvalSymlinks(path)
+	if err == nil {
+		err = checkInside(p.path, resolved)
+	}
+	if err != nil {
+		return false, &os.PathError{Op: "resolve", Path: path, Err: err}
+	}
+	info, err := os.Stat(resolved)
+	if err != nil {
+		return false, err
+	}
+	return info.Mode().IsRegular(), nil
+}
+
+// Gets the current secret map.
+func (p *secretsProvider) secrets() map[string]string {
+	return p.snapshot.Load().(*secretSnapshot).envSecrets
+}
+
+func (p *secretsProvider) Name() string {
+	return string(SourceSecret)
+}
+
+func (p *secretsProvider) Lookup(key string) (string, bool) {
+	res, ok := p.secrets()[key]
+	return res, ok
+}
+
+func (p *secretsProvider) Sensitive() bool {
+	return true
+}
+
+// Gets the path of the file a secret was read from.
+func (p *secretsProvider) Location(key string) string {
+	return p.snapshot.Load().(*secretSnapshot).paths[key]
+}
+
+func (p *secretsProvider) Keys() []string {
+	secrets := p.secrets()
+	res := make([]string, 0, len(secrets))
+	for key := range secrets {
+		res = append(res, key)
+	}
+	return res
+}
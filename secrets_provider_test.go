@@ -0,0 +1,160 @@
+package env_wrapper
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+// Writes files relative to dir and creates their parent directories.
+func writeFiles(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	for name, value := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(value), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// Creates symlinks relative to dir.
+func writeLinks(t *testing.T, dir string, links map[string]string) {
+	t.Helper()
+	for name, target := range links {
+		if err := os.Symlink(target, filepath.Join(dir, name)); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestKubernetesLayout(t *testing.T) {
+	outside := t.TempDir()
+	writeFiles(t, outside, map[string]string{"leak": "outside"})
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"..2024_01_01/db-password": "pass\n",
+		"..2024_01_01/api.key":     "key",
+		"..2024_01_01/sub/nested":  "nested",
+		"plain-file":               "plain",
+		"ENV_OLD":                  "old",
+	})
+	writeLinks(t, dir, map[string]string{
+		"..data":      "..2024_01_01",
+		"db-password": "..data/db-password",
+		"api.key":     filepath.Join(dir, "..data", "api.key"),
+		"sub":         "..data/sub",
+		"escape":      filepath.Join(outside, "leak"),
+		"relescape":   "../" + filepath.Base(outside) + "/leak",
+		"dangling":    "..data/missing",
+	})
+
+	p, err := NewSecretsProviderE(dir, KubernetesLayout())
+	var serr *ScanError
+	if !errors.As(err, &serr) || len(serr.Errs) != 3 || !errors.Is(err, ErrOutsideDir) {
+		t.Fatalf("expected the escaping and dangling links to be reported, got %v", err)
+	}
+	keys := p.Keys()
+	sort.Strings(keys)
+	want := []string{"API.KEY", "DB_PASSWORD", "ENV_OLD", "PLAIN_FILE"}
+	if !reflect.DeepEqual(keys, want) {
+		t.Errorf("got keys %v, want %v", keys, want)
+	}
+	values := map[string]string{
+		"DB_PASSWORD": "pass",
+		"API.KEY":     "key",
+		"PLAIN_FILE":  "plain",
+		"ENV_OLD":     "old",
+		"ESCAPE":      "",
+		"RELESCAPE":   "",
+		"SUB":         "",
+		"..DATA":      "",
+	}
+	for key, want := range values {
+		got, ok := p.Lookup(key)
+		if got != want || ok != (len(want) > 0) {
+			t.Errorf("%s: got %q (%t), want %q", key, got, ok, want)
+		}
+	}
+	if got := NewKubernetes(dir).GetString("db_password"); got != "pass" {
+		t.Errorf("expected NewKubernetes to read the layout, got %q", got)
+	}
+}
+
+func TestDefaultLayoutIgnoresUnprefixed(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{"db-password": "pass", "ENV_DB_PASSWORD": "prefixed", "ENV_DIR/x": "x"})
+	p := NewSecretsProvider(dir)
+	if keys := p.Keys(); !reflect.DeepEqual(keys, []string{"DB_PASSWORD"}) {
+		t.Errorf("expected only the prefixed file, got %v", keys)
+	}
+	if got, _ := p.Lookup("DB_PASSWORD"); got != "prefixed" {
+		t.Errorf("got %q", got)
+	}
+}
+
+func TestSecretNaming(t *testing.T) {
+	files := map[string]string{
+		"ENV_DB_PASSWORD":          "env",
+		"myapp_db_password":        "app",
+		"MYAPP_api-key":            "dash",
+		"myapp_tls.cert":           "dots",
+		"other_db_password":        "other",
+		"myapp_":                   "empty",
+		"ENV_MYAPP_smtp-host.name": "both",
+	}
+	tests := []struct {
+		name string
+		opts []Option
+		want map[string]string
+	}{
+		{
+			name: "default",
+			want: map[string]string{"DB_PASSWORD": "env", "MYAPP_SMTP-HOST.NAME": "both"},
+		},
+		{
+			name: "prefix",
+			opts: []Option{SecretPrefix("myapp_")},
+			want: map[string]string{"DB_PASSWORD": "app", "TLS.CERT": "dots"},
+		},
+		{
+			name: "namespace",
+			opts: []Option{SecretPrefix(""), Namespace("myapp")},
+			want: map[string]string{"DB_PASSWORD": "app", "API-KEY": "dash", "TLS.CERT": "dots"},
+		},
+		{
+			name: "mappers",
+			opts: []Option{SecretPrefix(""), Namespace("MyApp"), MapKeys(ChainMappers(DashToUnderscore, DotsToDoubleUnderscore))},
+			want: map[string]string{"DB_PASSWORD": "app", "API_KEY": "dash", "TLS__CERT": "dots"},
+		},
+		{
+			name: "prefix and namespace",
+			opts: []Option{Namespace("myapp"), MapKeys(ChainMappers(DashToUnderscore, DotsToDoubleUnderscore))},
+			want: map[string]string{"SMTP_HOST__NAME": "both"},
+		},
+		{
+			name: "kubernetes with prefix",
+			opts: []Option{KubernetesLayout(), SecretPrefix("ENV_")},
+			want: map[string]string{"DB_PASSWORD": "env", "MYAPP_SMTP_HOST.NAME": "both"},
+		},
+	}
+	dir := t.TempDir()
+	writeFiles(t, dir, files)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := NewSecretsProvider(dir, tt.opts...)
+			got := make(map[string]string)
+			for _, key := range p.Keys() {
+				got[key], _ = p.Lookup(key)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,24 @@
+package env_wrapper
+
+import (
+	"strings"
+)
+
+// Creates a view whose getters prepend "PREFIX_" to every name.
+// The view shares the providers, recorded violations and the access inventory with its parent.
+// Views can be nested, w.Sub("DB").Sub("PRIMARY") reads "HOST" from "DB_PRIMARY_HOST".
+func (w *env_wrapper) Sub(prefix string) *env_wrapper {
+	res := &env_wrapper{
+		core:   w.core,
+		prefix: w.prefix,
+	}
+	if prefix = strings.TrimSuffix(prefix, "_"); len(prefix) > 0 {
+		res.prefix += strings.ToUpper(prefix) + "_"
+	}
+	return res
+}
+
+// Gets the fully qualified upper case key of a name.
+func (w *env_wrapper) key(name string) string {
+	return w.prefix + strings.ToUpper(name)
+}
@@ -0,0 +1,56 @@
+package env_wrapper
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestSub(t *testing.T) {
+	w := newTestWrapper(t,
+		map[string]string{"DB_PRIMARY_PASSWORD": "secret"},
+		map[string]string{
+			"HOST":                "root",
+			"DB_HOST":             "db",
+			"DB_PRIMARY_HOST":     "primary",
+			"DB_REPLICA_HOST":     "replica",
+			"DB_REPLICA_PORT":     "x",
+			"DB_PRIMARY_PASSWORD": "env",
+		},
+	)
+	tests := []struct {
+		view *env_wrapper
+		name string
+		want string
+	}{
+		{w, "HOST", "root"},
+		{w.Sub("db"), "host", "db"},
+		{w.Sub("DB_"), "HOST", "db"},
+		{w.Sub(""), "HOST", "root"},
+		{w.Sub("DB").Sub("PRIMARY"), "HOST", "primary"},
+		{w.Sub("db").Sub("replica"), "HOST", "replica"},
+		{w.Sub("DB").Sub("PRIMARY"), "PASSWORD", "secret"},
+		{w.Sub("DB").Sub("MISSING"), "HOST", ""},
+	}
+	for _, tt := range tests {
+		if got := tt.view.GetString(tt.name); got != tt.want {
+			t.Errorf("%s%s: got %q, want %q", tt.view.prefix, tt.name, got, tt.want)
+		}
+	}
+
+	replica := w.Sub("DB").Sub("REPLICA")
+	_, err := replica.GetIntE("PORT")
+	var verr *ValueError
+	if !errors.As(err, &verr) || verr.Key != "DB_REPLICA_PORT" {
+		t.Errorf("expected the error to carry the qualified key, got %v", err)
+	}
+	if e := replica.Explain("host"); e.Key != "DB_REPLICA_HOST" || e.Value != "replica" {
+		t.Errorf("expected a qualified explanation, got %+v", e)
+	}
+	replica.Check("USER", Required())
+	if err := replica.Validate(); err == nil {
+		t.Error("expected the view to report its violation")
+	}
+	if err := w.Validate(); !errors.As(err, &verr) || verr.Key != "DB_REPLICA_USER" {
+		t.Errorf("expected the parent to report the violation of the view, got %v", err)
+	}
+}
@@ -0,0 +1,167 @@
+package env_wrapper
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Key supplied by a layer which no getter asked for.
+// Suggestions lists accessed keys with a similar name.
+type UnknownKey struct {
+	Key         string
+	Source      Source
+	Suggestions []string
+}
+
+func (u UnknownKey) String() string {
+	if len(u.Suggestions) == 0 {
+		return fmt.Sprintf("%s (%s)", u.Key, u.Source)
+	}
+	return fmt.Sprintf("%s (%s, did you mean %s?)", u.Key, u.Source, strings.Join(u.Suggestions, " or "))
+}
+
+// Lists every key which is supplied but was never accessed.
+type UnknownKeysError struct {
+	Keys []UnknownKey
+}
+
+func (e *UnknownKeysError) Error() string {
+	keys := make([]string, len(e.Keys))
+	for i, k := range e.Keys {
+		keys[i] = k.String()
+	}
+	return fmt.Sprintf("env_wrapper: unknown keys: %s", strings.Join(keys, "; "))
+}
+
+// Lists keys supplied by the providers which no getter, Unmarshal or constraint asked for so far.
+// Call it after startup or struct binding.
+// Process environment variables are only considered if they start with one of the prefixes,
+// every key of the other providers is considered.
+// With a "_FILE" provider in the chain KEY_FILE counts as accessed if KEY was accessed.
+func (w *env_wrapper) UnknownKeys(envPrefixes ...string) []UnknownKey {
+	files := false
+	for _, p := range w.providers {
+		if _, ok := p.(*fileEnvProvider); ok {
+			files = true
+		}
+	}
+	w.mu.Lock()
+	known := append([]string{}, w.accessOrder...)
+	accessed := make(map[string]bool, len(known))
+	for _, key := range known {
+		accessed[key] = true
+		if files {
+			accessed[key+fileSuffix] = true
+		}
+	}
+	w.mu.Unlock()
+
+	res := []UnknownKey{}
+	seen := make(map[string]bool)
+	for _, p := range w.providers {
+		ep, ok := p.(EnumeratingProvider)
+		if !ok {
+			continue
+		}
+		for _, key := range ep.Keys() {
+			if accessed[key] || seen[key] || (isProcessEnv(p) && !hasAnyPrefix(key, envPrefixes)) {
+				continue
+			}
+			seen[key] = true
+			res = append(res, UnknownKey{
+				Key:         key,
+				Source:      Source(p.Name()),
+				Suggestions: suggest(key, known),
+			})
+		}
+	}
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].Key < res[j].Key
+	})
+	return res
+}
+
+// Returns an UnknownKeysError if UnknownKeys reports any key.
+func (w *env_wrapper) CheckUnknown(envPrefixes ...string) error {
+	if res := w.UnknownKeys(envPrefixes...); len(res) > 0 {
+		return &UnknownKeysError{res}
+	}
+	return nil
+}
+
+// Reports if a provider serves the whole process environment.
+func isProcessEnv(p Provider) bool {
+	switch p.(type) {
+	case *envProvider, *fileEnvProvider:
+		return true
+	}
+	return false
+}
+
+func hasAnyPrefix(key string, prefixes []string) bool {
+	for _, prefix := range prefixes {
+		if strings.HasPrefix(key, strings.ToUpper(prefix)) {
+			return true
+		}
+	}
+	return false
+}
+
+// Gets the known keys within a small edit distance, closest first.
+func suggest(key string, known []string) []string {
+	type candidate struct {
+		key  string
+		dist int
+	}
+	limit := len(key) / 3
+	if limit < 1 {
+		limit = 1
+	}
+	var candidates []candidate
+	for _, k := range known {
+		if d := levenshtein(key, k); d <= limit {
+			candidates = append(candidates, candidate{k, d})
+		}
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].dist < candidates[j].dist
+	})
+	res := make([]string, len(candidates))
+	for i, c := range candidates {
+		res[i] = c.key
+	}
+	return res
+}
+
+// Calculates the edit distance of two strings.
+func levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	cur := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		cur[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			cur[j] = min3(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(rb)]
+}
+
+func min3(a, b, c int) int {
+	if b < a {
+		a = b
+	}
+	if c < a {
+		a = c
+	}
+	return a
+}
@@ -0,0 +1,33 @@
+package env_wrapper
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestUnknownKeys(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv("UNKNOWN_TEST_DB_PASSWORD_FILE", dir)
+	t.Setenv("UNKNOWN_TEST_DB_HOST", "db")
+	t.Setenv("UNKNOWN_TEST_DB_HOTS", "typo")
+	t.Setenv("UNKNOWN_TEST_OTHER_FILE", dir)
+	w := NewChain(NewEnvProvider(), NewFileEnvProvider(), NewMapProvider("map", map[string]string{"EXTRA": "1"}))
+	w.GetString("UNKNOWN_TEST_DB_HOST")
+	w.GetString("UNKNOWN_TEST_DB_PASSWORD")
+
+	var keys []string
+	var suggestions []string
+	for _, k := range w.UnknownKeys("UNKNOWN_TEST_") {
+		keys = append(keys, k.Key)
+		if k.Key == "UNKNOWN_TEST_DB_HOTS" {
+			suggestions = k.Suggestions
+		}
+	}
+	want := []string{"EXTRA", "UNKNOWN_TEST_DB_HOTS", "UNKNOWN_TEST_OTHER", "UNKNOWN_TEST_OTHER_FILE"}
+	if !reflect.DeepEqual(keys, want) {
+		t.Fatalf("expected %v, got %v", want, keys)
+	}
+	if !reflect.DeepEqual(suggestions, []string{"UNKNOWN_TEST_DB_HOST"}) {
+		t.Fatalf("unexpected suggestions %v", suggestions)
+	}
+}
@@ -0,0 +1,243 @@
+package env_wrapper
+
+import (
+	"encoding"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Fills the fields of the struct dst points to.
+// Fields are bound with an `env:"NAME"` tag and may provide a `default:"value"` tag.
+// String arrays are split with SplitList by the whitespace character or a custom `sep:","` tag.
+// Secret fields hold values which must not be printed.
+// Types registered with RegisterParser and types implementing encoding.TextUnmarshaler are supported as well.
+// Fields tagged like `env:"ROUTES,json"` are decoded from JSON.
+// Fields can be validated with the `required:"true"`, `min`, `max`, `oneof`, `regex` and `minlen` tags.
+// Untagged struct and struct pointer fields are walked recursively,
+// nil struct pointers stay nil unless one of their variables is set.
+// Every invalid field is reported in a single ValidationError.
+func (w *env_wrapper) Unmarshal(dst any) error {
+	rv := reflect.ValueOf(dst)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return errors.New("env_wrapper: Unmarshal needs a non-nil struct pointer")
+	}
+	var errs []*ValueError
+	if err := w.unmarshalStruct(rv.Elem(), &errs); err != nil {
+		return err
+	}
+	return newValidationError(errs)
+}
+
+// Binds every exported field of a struct value.
+// Invalid values are collected in errs, other errors are returned.
+func (w *env_wrapper) unmarshalStruct(sv reflect.Value, errs *[]*ValueError) error {
+	st := sv.Type()
+	for i := 0; i < st.NumField(); i++ {
+		field := st.Field(i)
+		if len(field.PkgPath) > 0 {
+			continue
+		}
+		fv := sv.Field(i)
+		name, tagged := field.Tag.Lookup("env")
+		if !tagged {
+			if err := w.unmarshalNested(fv, errs); err != nil {
+				return err
+			}
+			continue
+		}
+		if name == "-" {
+			continue
+		}
+		name, opt, _ := strings.Cut(name, ",")
+		err := w.unmarshalField(fv, name, field.Tag, opt == "json")
+		var verr *ValueError
+		var vaerr *ValidationError
+		switch {
+		case errors.As(err, &vaerr):
+			*errs = append(*errs, vaerr.Errs...)
+		case errors.As(err, &verr):
+			*errs = append(*errs, verr)
+		case err != nil:
+			return fmt.Errorf("env_wrapper: field %s: %w", field.Name, err)
+		}
+	}
+	return nil
+}
+
+// Walks untagged struct and struct pointer fields.
+// Nil struct pointers are optional sections, they are only allocated if one of their variables is set.
+func (w *env_wrapper) unmarshalNested(fv reflect.Value, errs *[]*ValueError) error {
+	switch {
+	case fv.Kind() == reflect.Struct:
+		return w.unmarshalStruct(fv, errs)
+	case fv.Kind() == reflect.Ptr && fv.Type().Elem().Kind() == reflect.Struct:
+		if fv.IsNil() {
+			if !w.anySet(fv.Type().Elem(), map[reflect.Type]bool{}) {
+				return nil
+			}
+			fv.Set(reflect.New(fv.Type().Elem()))
+		}
+		return w.unmarshalStruct(fv.Elem(), errs)
+	}
+	return nil
+}
+
+// Reports if a variable of a struct type or its nested structs is set or can't be read.
+func (w *env_wrapper) anySet(st reflect.Type, seen map[reflect.Type]bool) bool {
+	if seen[st] {
+		return false
+	}
+	seen[st] = true
+	for i := 0; i < st.NumField(); i++ {
+		field := st.Field(i)
+		if len(field.PkgPath) > 0 {
+			continue
+		}
+		name, tagged := field.Tag.Lookup("env")
+		if !tagged {
+			ft := field.Type
+			if ft.Kind() == reflect.Ptr {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct && w.anySet(ft, seen) {
+				return true
+			}
+			continue
+		}
+		if name == "-" {
+			continue
+		}
+		name, _, _ = strings.Cut(name, ",")
+		if _, src, err := w.get(name); err != nil || src != SourceNone {
+			return true
+		}
+	}
+	return false
+}
+
+// Binds a single tagged field, JSON values are decoded if asJSON is set.
+// Fields stay untouched if neither the variable nor a default is set or a rule is violated.
+func (w *env_wrapper) unmarshalField(fv reflect.Value, name string, tag reflect.StructTag, asJSON bool) error {
+	rules, err := rulesFromTag(tag)
+	if err != nil {
+		return err
+	}
+	w.trackSecretType(name, fv.Type())
+	var defptr *string
+	if defval, ok := tag.Lookup("default"); ok {
+		defptr = &defval
+		w.trackDefault(name, defval)
+	}
+	r, err := w.resolve(name)
+	w.record(name, fv.Type().String(), defptr, r.source)
+	if err != nil {
+		return err
+	}
+	if err := w.validate(name, r, rules); err != nil {
+		return err
+	}
+	if r.source == SourceNone {
+		r = resolved{tag.Get("default"), SourceDefault, false}
+	}
+	if len(r.value) == 0 {
+		return nil
+	}
+	target := fv
+	isPtr := fv.Kind() == reflect.Ptr
+	if isPtr {
+		target = reflect.New(fv.Type().Elem()).Elem()
+	}
+	if asJSON {
+		err = decodeJSON([]byte(r.value), target.Addr().Interface())
+	} else {
+		err = setValue(target, r.value, tag)
+	}
+	if err != nil {
+		return w.valueError(name, r, err)
+	}
+	if isPtr {
+		fv.Set(target.Addr())
+	}
+	return nil
+}
+
+var (
+	// Type of Secret fields which are bound directly instead of walked.
+	secretType = reflect.TypeOf(Secret{})
+	// Type of duration fields which are parsed instead of handled as integer.
+	durationType = reflect.TypeOf(time.Duration(0))
+)
+
+// Parses a string into a field value.
+func setValue(fv reflect.Value, strval string, tag reflect.StructTag) error {
+	if entry, ok := registeredParser(fv.Type()); ok {
+		return entry.set(strval, fv)
+	}
+	if fv.Addr().Type().Implements(textUnmarshalerType) {
+		return fv.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(strval))
+	}
+	switch fv.Type() {
+	case secretType:
+		fv.Set(reflect.ValueOf(NewSecret(strval)))
+		return nil
+	case durationType:
+		res, err := time.ParseDuration(strval)
+		if err != nil {
+			return err
+		}
+		fv.SetInt(int64(res))
+		return nil
+	}
+	switch fv.Kind() {
+	case reflect.String:
+		fv.SetString(strval)
+	case reflect.Bool:
+		res, err := strconv.ParseBool(strval)
+		if err != nil {
+			return err
+		}
+		fv.SetBool(res)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		res, err := strconv.ParseInt(strval, 10, fv.Type().Bits())
+		if err != nil {
+			return err
+		}
+		fv.SetInt(res)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		res, err := strconv.ParseUint(strval, 10, fv.Type().Bits())
+		if err != nil {
+			return err
+		}
+		fv.SetUint(res)
+	case reflect.Float32, reflect.Float64:
+		res, err := strconv.ParseFloat(strval, fv.Type().Bits())
+		if err != nil {
+			return err
+		}
+		fv.SetFloat(res)
+	case reflect.Slice:
+		if fv.Type().Elem().Kind() != reflect.String {
+			return fmt.Errorf("unsupported type %s", fv.Type())
+		}
+		seperator, ok := tag.Lookup("sep")
+		if !ok {
+			seperator = " "
+		}
+		parts, err := SplitList(strval, seperator)
+		if err != nil {
+			return err
+		}
+		res := reflect.MakeSlice(fv.Type(), len(parts), len(parts))
+		for i, s := range parts {
+			res.Index(i).SetString(s)
+		}
+		fv.Set(res)
+	default:
+		return fmt.Errorf("unsupported type %s", fv.Type())
+	}
+	return nil
+}
@@ -0,0 +1,180 @@
+package env_wrapper
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+type testTLS struct {
+	Cert string `env:"TLS_CERT"`
+	Key  Secret `env:"TLS_KEY"`
+}
+
+type testDB struct {
+	User string `env:"DB_USER"`
+}
+
+type testRoute struct {
+	Path string `json:"path"`
+	Port int    `json:"port"`
+}
+
+type testConfig struct {
+	Host    string        `env:"HOST" default:"localhost"`
+	Port    int           `env:"PORT" default:"8080"`
+	Debug   *bool         `env:"DEBUG"`
+	Timeout time.Duration `env:"TIMEOUT" default:"5s"`
+	Tags    []string      `env:"TAGS"`
+	Peers   []string      `env:"PEERS" sep:","`
+	Routes  []testRoute   `env:"ROUTES,json"`
+	Token   Secret        `env:"TOKEN"`
+	Ignored string        `env:"-"`
+	DB      testDB
+	TLS     *testTLS
+}
+
+func TestUnmarshal(t *testing.T) {
+	debug := true
+	tests := []struct {
+		name    string
+		secrets map[string]string
+		env     map[string]string
+		want    testConfig
+	}{
+		{
+			name: "defaults",
+			want: testConfig{Host: "localhost", Port: 8080, Timeout: 5 * time.Second},
+		},
+		{
+			name: "values",
+			env: map[string]string{
+				"HOST":    "example.com",
+				"PORT":    "443",
+				"DEBUG":   "true",
+				"TIMEOUT": "1m",
+				"TAGS":    " a  b ",
+				"PEERS":   "x, y,,z",
+				"ROUTES":  `[{"path":"/","port":80}]`,
+				"DB_USER": "admin",
+				"IGNORED": "set",
+			},
+			want: testConfig{
+				Host:    "example.com",
+				Port:    443,
+				Debug:   &debug,
+				Timeout: time.Minute,
+				Tags:    []string{"a", "b"},
+				Peers:   []string{"x", "y", "z"},
+				Routes:  []testRoute{{"/", 80}},
+				DB:      testDB{User: "admin"},
+			},
+		},
+		{
+			name:    "optional section",
+			secrets: map[string]string{"TLS_KEY": "key"},
+			want: testConfig{
+				Host:    "localhost",
+				Port:    8080,
+				Timeout: 5 * time.Second,
+				TLS:     &testTLS{Key: NewSecret("key")},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := newTestWrapper(t, tt.secrets, tt.env)
+			var got testConfig
+			if err := w.Unmarshal(&got); err != nil {
+				t.Fatal(err)
+			}
+			if got.TLS != nil && tt.want.TLS != nil && got.TLS.Key.Reveal() == tt.want.TLS.Key.Reveal() {
+				got.TLS.Key = tt.want.TLS.Key
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUnmarshalKeepsSection(t *testing.T) {
+	w := newTestWrapper(t, nil, nil)
+	cfg := testConfig{TLS: &testTLS{Cert: "cert"}}
+	if err := w.Unmarshal(&cfg); err != nil {
+		t.Fatal(err)
+	}
+	if cfg.TLS == nil || cfg.TLS.Cert != "cert" {
+		t.Errorf("expected the existing section to be kept, got %+v", cfg.TLS)
+	}
+}
+
+func TestUnmarshalSecret(t *testing.T) {
+	var cfg struct {
+		Token  Secret  `env:"TOKEN"`
+		APIKey *Secret `env:"API_KEY"`
+		Port   Secret  `env:"PORT" min:"1"`
+	}
+	w := newTestWrapper(t, map[string]string{"TOKEN": "hunter2"}, map[string]string{"API_KEY": "abc", "PORT": "0"})
+	err := w.Unmarshal(&cfg)
+	if err == nil || !strings.Contains(err.Error(), redacted) {
+		t.Fatalf("expected a redacted violation, got %v", err)
+	}
+	if cfg.Token.Reveal() != "hunter2" || cfg.APIKey == nil || cfg.APIKey.Reveal() != "abc" {
+		t.Errorf("unexpected secrets %q %v", cfg.Token.Reveal(), cfg.APIKey)
+	}
+	if s := cfg.Token.String(); strings.Contains(s, "hunter2") {
+		t.Errorf("secret printed as %q", s)
+	}
+}
+
+func TestUnmarshalValidation(t *testing.T) {
+	var cfg struct {
+		Name  string  `env:"NAME" required:"true"`
+		Port  int     `env:"PORT" min:"1" max:"65535"`
+		Mode  string  `env:"MODE" oneof:"dev prod"`
+		ID    string  `env:"ID" regex:"^[a-z]+$"`
+		Pass  string  `env:"PASS" minlen:"8"`
+		Ratio float64 `env:"RATIO"`
+		OK    string  `env:"OK" oneof:"a b"`
+	}
+	w := newTestWrapper(t, nil, map[string]string{
+		"PORT":  "70000",
+		"MODE":  "test",
+		"ID":    "A1",
+		"PASS":  "short",
+		"RATIO": "x",
+		"OK":    "a",
+	})
+	err := w.Unmarshal(&cfg)
+	var verr *ValidationError
+	if !errors.As(err, &verr) {
+		t.Fatalf("expected a ValidationError, got %v", err)
+	}
+	var keys []string
+	for _, e := range verr.Errs {
+		keys = append(keys, e.Key)
+	}
+	want := []string{"NAME", "PORT", "MODE", "ID", "PASS", "RATIO"}
+	if !reflect.DeepEqual(keys, want) {
+		t.Errorf("got violations for %v, want %v", keys, want)
+	}
+	if cfg.Port != 0 || cfg.OK != "a" {
+		t.Errorf("expected invalid fields to stay untouched, got %+v", cfg)
+	}
+}
+
+func TestUnmarshalInvalid(t *testing.T) {
+	w := newTestWrapper(t, nil, nil)
+	var cfg struct {
+		Port int `env:"PORT" min:"x"`
+	}
+	tests := []any{nil, cfg, &cfg, new(int)}
+	for i, dst := range tests {
+		if err := w.Unmarshal(dst); err == nil {
+			t.Errorf("%d: expected an error for %T", i, dst)
+		}
+	}
+}
@@ -0,0 +1,259 @@
+package env_wrapper
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// Checks the raw value of a variable, present is false if no layer supplied a value.
+// Except Required the builtin rules accept missing variables.
+type Rule func(strval string, present bool) error
+
+// Lists every violated rule and constraint.
+type ValidationError struct {
+	Errs        []*ValueError
+	Constraints []*ConstraintError
+}
+
+func (e *ValidationError) Error() string {
+	msgs := []string{}
+	for _, err := range e.Unwrap() {
+		msgs = append(msgs, strings.TrimPrefix(err.Error(), "env_wrapper: "))
+	}
+	return fmt.Sprintf("env_wrapper: validation failed: %s", strings.Join(msgs, "; "))
+}
+
+func (e *ValidationError) Unwrap() []error {
+	res := make([]error, 0, len(e.Errs)+len(e.Constraints))
+	for _, err := range e.Errs {
+		res = append(res, err)
+	}
+	for _, err := range e.Constraints {
+		res = append(res, err)
+	}
+	return res
+}
+
+// Reports if any violation matches target.
+// errors.Is only unwraps []error since Go 1.20.
+func (e *ValidationError) Is(target error) bool {
+	return anyIs(e.Unwrap(), target)
+}
+
+// Finds the first violation which matches target.
+// errors.As only unwraps []error since Go 1.20.
+func (e *ValidationError) As(target any) bool {
+	return anyAs(e.Unwrap(), target)
+}
+
+// Creates a ValidationError or returns nil if there are no violations.
+func newValidationError(errs []*ValueError) error {
+	if len(errs) == 0 {
+		return nil
+	}
+	return &ValidationError{Errs: errs}
+}
+
+// Requires a non empty value.
+func Required() Rule {
+	return func(strval string, present bool) error {
+		if !present {
+			return ErrNotSet
+		}
+		return nil
+	}
+}
+
+// Requires a number greater or equal to min.
+func Min(min float64) Rule {
+	return func(strval string, present bool) error {
+		if !present {
+			return nil
+		}
+		res, err := strconv.ParseFloat(strval, 64)
+		if err != nil {
+			return errors.New("value is not a number")
+		}
+		if res < min {
+			return fmt.Errorf("value must be at least %v", min)
+		}
+		return nil
+	}
+}
+
+// Requires a number less or equal to max.
+func Max(max float64) Rule {
+	return func(strval string, present bool) error {
+		if !present {
+			return nil
+		}
+		res, err := strconv.ParseFloat(strval, 64)
+		if err != nil {
+			return errors.New("value is not a number")
+		}
+		if res > max {
+			return fmt.Errorf("value must be at most %v", max)
+		}
+		return nil
+	}
+}
+
+// Requires one of the allowed values.
+func OneOf(values ...string) Rule {
+	return func(strval string, present bool) error {
+		if !present {
+			return nil
+		}
+		for _, v := range values {
+			if strval == v {
+				return nil
+			}
+		}
+		return fmt.Errorf("value must be one of %s", strings.Join(values, ", "))
+	}
+}
+
+// Requires a value matching a regular expression.
+// Panics if the expression can't be compiled.
+func Matches(pattern string) Rule {
+	return matches(regexp.MustCompile(pattern))
+}
+
+func matches(re *regexp.Regexp) Rule {
+	return func(strval string, present bool) error {
+		if !present || re.MatchString(strval) {
+			return nil
+		}
+		return fmt.Errorf("value must match %s", re)
+	}
+}
+
+// Requires a value with at least n characters, for example a secret.
+func MinLen(n int) Rule {
+	return func(strval string, present bool) error {
+		if !present || utf8.RuneCountInString(strval) >= n {
+			return nil
+		}
+		return fmt.Errorf("value must be at least %d characters long", n)
+	}
+}
+
+// Validates a variable with rules.
+// Violations are returned and recorded for Validate.
+func (w *env_wrapper) Check(name string, rules ...Rule) error {
+	r, err := w.resolve(name)
+	if err != nil {
+		return err
+	}
+	return w.validate(name, r, rules)
+}
+
+// Returns every violation recorded by Check, CheckConstraints, the getters and Unmarshal as a ValidationError.
+// Repeated violations of the same key and rule are reported once with the latest value.
+// Returns nil if no rule was violated.
+func (w *env_wrapper) Validate() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if len(w.violations) == 0 && len(w.constraintViolations) == 0 {
+		return nil
+	}
+	return &ValidationError{
+		append([]*ValueError{}, w.violations...),
+		append([]*ConstraintError{}, w.constraintViolations...),
+	}
+}
+
+// Applies rules to a value and records violations.
+func (w *env_wrapper) validate(name string, r resolved, rules []Rule) error {
+	var errs []*ValueError
+	for _, rule := range rules {
+		if err := rule(r.value, r.source != SourceNone); err != nil {
+			errs = append(errs, w.valueError(name, r, err))
+		}
+	}
+	if len(errs) > 0 {
+		w.mu.Lock()
+		for _, err := range errs {
+			w.addViolation(err)
+		}
+		w.mu.Unlock()
+	}
+	return newValidationError(errs)
+}
+
+// Records a violation, it replaces a previous violation of the same key and rule.
+// c.mu has to be held.
+func (c *core) addViolation(err *ValueError) {
+	for i, v := range c.violations {
+		if v.Key == err.Key && v.Err.Error() == err.Err.Error() {
+			c.violations[i] = err
+			return
+		}
+	}
+	c.violations = append(c.violations, err)
+}
+
+// Records a constraint violation, it replaces a previous violation of the same constraint.
+// c.mu has to be held.
+func (c *core) addConstraintViolation(err *ConstraintError) {
+	for i, v := range c.constraintViolations {
+		if v.Msg == err.Msg {
+			c.constraintViolations[i] = err
+			return
+		}
+	}
+	c.constraintViolations = append(c.constraintViolations, err)
+}
+
+// Creates the rules of the `required`, `min`, `max`, `oneof`, `regex` and `minlen` struct tags.
+// Allowed values of `oneof` are separated by whitespace.
+func rulesFromTag(tag reflect.StructTag) ([]Rule, error) {
+	var res []Rule
+	if tval, ok := tag.Lookup("required"); ok {
+		required, err := strconv.ParseBool(tval)
+		if err != nil {
+			return nil, fmt.Errorf("invalid required tag: %w", err)
+		}
+		if required {
+			res = append(res, Required())
+		}
+	}
+	if tval, ok := tag.Lookup("min"); ok {
+		min, err := strconv.ParseFloat(tval, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid min tag: %w", err)
+		}
+		res = append(res, Min(min))
+	}
+	if tval, ok := tag.Lookup("max"); ok {
+		max, err := strconv.ParseFloat(tval, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid max tag: %w", err)
+		}
+		res = append(res, Max(max))
+	}
+	if tval, ok := tag.Lookup("oneof"); ok {
+		res = append(res, OneOf(strings.Fields(tval)...))
+	}
+	if tval, ok := tag.Lookup("regex"); ok {
+		re, err := regexp.Compile(tval)
+		if err != nil {
+			return nil, fmt.Errorf("invalid regex tag: %w", err)
+		}
+		res = append(res, matches(re))
+	}
+	if tval, ok := tag.Lookup("minlen"); ok {
+		n, err := strconv.Atoi(tval)
+		if err != nil {
+			return nil, fmt.Errorf("invalid minlen tag: %w", err)
+		}
+		res = append(res, MinLen(n))
+	}
+	return res, nil
+}
@@ -0,0 +1,31 @@
+package env_wrapper
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestValidateDeduplicates(t *testing.T) {
+	w := newTestWrapper(t, nil, map[string]string{"PORT": "70000", "MODE": "x"})
+	for i := 0; i < 3; i++ {
+		w.GetIntDef("PORT", 80)
+		Get(w, "PORT", 80, Max(65535))
+		Get(w, "MODE", "a", OneOf("a", "b"), MinLen(2))
+		w.Check("MISSING", Required())
+		w.CheckConstraints(ExactlyOneOf("A", "B"))
+	}
+	err := w.Validate()
+	var verr *ValidationError
+	if !errors.As(err, &verr) {
+		t.Fatalf("expected a ValidationError, got %v", err)
+	}
+	if len(verr.Errs) != 4 || len(verr.Constraints) != 1 {
+		t.Fatalf("expected 4 violations and 1 constraint violation, got %v", err)
+	}
+
+	verr.Errs[0] = nil
+	verr.Constraints = append(verr.Constraints[:0], nil)
+	if err := w.Validate(); !errors.As(err, &verr) || verr.Errs[0] == nil || verr.Constraints[0] == nil {
+		t.Fatal("expected Validate to return copies")
+	}
+}
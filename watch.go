@@ -0,0 +1,147 @@
+package env_wrapper
+
+import (
+	"time"
+)
+
+// Default interval used to poll for changes if inotify isn't available.
+const defaultPollInterval = 10 * time.Second
+
+// Implemented by providers which can reload their values at runtime.
+type WatchableProvider interface {
+	Provider
+	// Starts watching for changes in the background.
+	Watch(pollInterval time.Duration) error
+	// Registers a callback which is called for every changed key.
+	OnChange(fn func(key, old, new string))
+	// Stops watching for changes.
+	Close() error
+}
+
+// Starts watching every watchable provider for changes.
+// inotify is used if possible, otherwise the sources are polled with the given interval.
+func (w *env_wrapper) Watch(pollInterval time.Duration) error {
+	for _, p := range w.providers {
+		if wp, ok := p.(WatchableProvider); ok {
+			if err := wp.Watch(pollInterval); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// Registers a callback which is called for every changed key of a watchable provider.
+// Removed keys are reported with an empty new value, added keys with an empty old value.
+func (w *env_wrapper) OnChange(fn func(key, old, new string)) {
+	for _, p := range w.providers {
+		if wp, ok := p.(WatchableProvider); ok {
+			wp.OnChange(fn)
+		}
+	}
+}
+
+// Stops watching every watchable provider.
+func (w *env_wrapper) Close() error {
+	var res error
+	for _, p := range w.providers {
+		if wp, ok := p.(WatchableProvider); ok {
+			if err := wp.Close(); err != nil && res == nil {
+				res = err
+			}
+		}
+	}
+	return res
+}
+
+// Secret which changed during a reload.
+type change struct {
+	key, old, new string
+}
+
+// Reads the secret directory again and swaps the secret map.
+// Entries which can't be read keep their previous value,
+// every value is kept if the directory is missing, for example during a swap.
+// Registered callbacks are called for every changed key after the swap.
+func (p *secretsProvider) Reload() {
+	p.mu.Lock()
+	prev := p.snapshot.Load().(*secretSnapshot)
+	snapshot, err := p.scan()
+	if err != nil || snapshot.unlisted {
+		snapshot = snapshot.keep(prev)
+	}
+	p.snapshot.Store(snapshot)
+	listeners := append([]func(key, old, new string){}, p.listeners...)
+	p.mu.Unlock()
+
+	old, res := prev.envSecrets, snapshot.envSecrets
+	var changes []change
+	for key, oval := range old {
+		if nval, ok := res[key]; !ok || nval != oval {
+			changes = append(changes, change{key, oval, nval})
+		}
+	}
+	for key, nval := range res {
+		if _, ok := old[key]; !ok {
+			changes = append(changes, change{key, "", nval})
+		}
+	}
+	for _, c := range changes {
+		for _, fn := range listeners {
+			fn(c.key, c.old, c.new)
+		}
+	}
+}
+
+func (p *secretsProvider) OnChange(fn func(key, old, new string)) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.listeners = append(p.listeners, fn)
+}
+
+func (p *secretsProvider) Watch(pollInterval time.Duration) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.stop != nil {
+		return nil
+	}
+	if pollInterval <= 0 {
+		pollInterval = defaultPollInterval
+	}
+	stop := make(chan struct{})
+	p.stop = stop
+	fallback := func() {
+		go p.poll(pollInterval, stop)
+	}
+	if err := watchNotify(p.path, p.Reload, fallback, stop); err != nil {
+		fallback()
+	}
+	return nil
+}
+
+// Reloads the secret directory periodically until stop is closed.
+func (p *secretsProvider) poll(interval time.Duration, stop chan struct{}) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			p.Reload()
+		case <-stop:
+			return
+		}
+	}
+}
+
+func (p *secretsProvider) Close() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.stop != nil {
+		close(p.stop)
+		p.stop = nil
+	}
+	return nil
+}
@@ -0,0 +1,68 @@
+//go:build linux
+// +build linux
+
+package env_wrapper
+
+import (
+	"os"
+	"syscall"
+	"unsafe"
+)
+
+// Events which indicate a changed directory entry.
+const inotifyMask = syscall.IN_CREATE | syscall.IN_DELETE | syscall.IN_CLOSE_WRITE |
+	syscall.IN_MOVED_FROM | syscall.IN_MOVED_TO | syscall.IN_ATTRIB | syscall.IN_DELETE_SELF | syscall.IN_MOVE_SELF
+
+// Events after which the watched directory is gone and the watch is dead.
+const inotifyEnd = syscall.IN_DELETE_SELF | syscall.IN_MOVE_SELF | syscall.IN_IGNORED
+
+// Calls reload for every inotify event of a directory until stop is closed.
+// If the directory is deleted or moved the watch ends and fallback is called.
+func watchNotify(path string, reload func(), fallback func(), stop chan struct{}) error {
+	fd, err := syscall.InotifyInit1(syscall.IN_CLOEXEC | syscall.IN_NONBLOCK)
+	if err != nil {
+		return err
+	}
+	if _, err := syscall.InotifyAddWatch(fd, path, inotifyMask); err != nil {
+		syscall.Close(fd)
+		return err
+	}
+	file := os.NewFile(uintptr(fd), "inotify")
+	done := make(chan struct{})
+	go func() {
+		select {
+		case <-stop:
+			file.Close()
+		case <-done:
+		}
+	}()
+	go func() {
+		buf := make([]byte, 64*(syscall.SizeofInotifyEvent+syscall.NAME_MAX+1))
+		for {
+			n, err := file.Read(buf)
+			if err != nil {
+				return
+			}
+			reload()
+			if watchEnded(buf[:n]) {
+				close(done)
+				file.Close()
+				fallback()
+				return
+			}
+		}
+	}()
+	return nil
+}
+
+// Reports if a buffer of inotify events contains an event which ends the watch.
+func watchEnded(buf []byte) bool {
+	for off := 0; off+syscall.SizeofInotifyEvent <= len(buf); {
+		event := (*syscall.InotifyEvent)(unsafe.Pointer(&buf[off]))
+		if event.Mask&inotifyEnd != 0 {
+			return true
+		}
+		off += syscall.SizeofInotifyEvent + int(event.Len)
+	}
+	return false
+}
@@ -0,0 +1,13 @@
+//go:build !linux
+// +build !linux
+
+package env_wrapper
+
+import (
+	"errors"
+)
+
+// Reports that inotify isn't available so the caller falls back to polling.
+func watchNotify(path string, reload func(), fallback func(), stop chan struct{}) error {
+	return errors.New("inotify is not supported on this platform")
+}
@@ -0,0 +1,241 @@
+package env_wrapper
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestReloadNotifiesWithoutLock(t *testing.T) {
+	dir := t.TempDir()
+	p := NewSecretsProvider(dir)
+	var changes []string
+	p.OnChange(func(key, old, new string) {
+		changes = append(changes, key+"="+new)
+		// Callbacks may use the provider again.
+		p.OnChange(func(key, old, new string) {})
+		p.Close()
+	})
+	if err := os.WriteFile(filepath.Join(dir, "ENV_TOKEN"), []byte("a"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	done := make(chan struct{})
+	go func() {
+		p.Reload()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Reload deadlocked")
+	}
+	if len(changes) != 1 || changes[0] != "TOKEN=a" {
+		t.Fatalf("unexpected changes %q", changes)
+	}
+}
+
+func TestReloadKeepsValuesOnScanError(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "secrets")
+	if err := os.Mkdir(dir, 0o700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "ENV_TOKEN"), []byte("a"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	p, err := NewSecretsProviderE(dir, Strict())
+	if err != nil {
+		t.Fatal(err)
+	}
+	var changes int
+	p.OnChange(func(key, old, new string) {
+		changes++
+	})
+	// A missing directory is an error in strict mode, like a half finished swap.
+	if err := os.RemoveAll(dir); err != nil {
+		t.Fatal(err)
+	}
+	p.Reload()
+	if val, ok := p.Lookup("TOKEN"); !ok || val != "a" || changes != 0 {
+		t.Fatalf("expected the previous value without changes, got %q, %v, %d changes", val, ok, changes)
+	}
+}
+
+func TestSnapshotKeep(t *testing.T) {
+	old := &secretSnapshot{
+		envSecrets: map[string]string{"A": "1", "B": "2", "C": "3"},
+		paths:      map[string]string{"A": "/a", "B": "/b", "C": "/c"},
+	}
+	s := &secretSnapshot{
+		envSecrets: map[string]string{"A": "10"},
+		paths:      map[string]string{"A": "/a"},
+		failed:     map[string]bool{"B": true, "D": true},
+	}
+	res := s.keep(old)
+	if len(res.envSecrets) != 2 || res.envSecrets["A"] != "10" || res.envSecrets["B"] != "2" || res.paths["B"] != "/b" {
+		t.Fatalf("unexpected snapshot %v", res.envSecrets)
+	}
+	if res := (&secretSnapshot{unlisted: true}).keep(old); res != old {
+		t.Fatal("expected the previous snapshot")
+	}
+}
+
+func TestReloadKeepsValuesWhenDirMissing(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "secrets")
+	if err := os.Mkdir(dir, 0o700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "ENV_TOKEN"), []byte("a"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	p := NewSecretsProvider(dir)
+	var changes int
+	p.OnChange(func(key, old, new string) {
+		changes++
+	})
+	if err := os.RemoveAll(dir); err != nil {
+		t.Fatal(err)
+	}
+	p.Reload()
+	if val, ok := p.Lookup("TOKEN"); !ok || val != "a" || changes != 0 {
+		t.Fatalf("expected the previous value without changes, got %q, %v, %d changes", val, ok, changes)
+	}
+}
+
+// Registers a callback which forwards changes of a key.
+func watchKey(p *secretsProvider, key string) chan string {
+	res := make(chan string, 16)
+	p.OnChange(func(k, old, new string) {
+		if k == key {
+			res <- new
+		}
+	})
+	return res
+}
+
+// Waits for a changed value.
+func waitChange(t *testing.T, changes chan string, want string) {
+	t.Helper()
+	timeout := time.After(5 * time.Second)
+	for {
+		select {
+		case got := <-changes:
+			if got == want {
+				return
+			}
+		case <-timeout:
+			t.Fatalf("no change to %q", want)
+		}
+	}
+}
+
+// Replaces a file atomically like a secret rotation.
+func rotate(t *testing.T, path, value string) {
+	t.Helper()
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, []byte(value), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestWatchRotation(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "ENV_TOKEN")
+	rotate(t, path, "a")
+	p := NewSecretsProvider(dir)
+	changes := watchKey(p, "TOKEN")
+	// A long interval makes sure the change is picked up by inotify where available.
+	if err := p.Watch(time.Hour); err != nil {
+		t.Fatal(err)
+	}
+	defer p.Close()
+
+	rotate(t, path, "b")
+	waitChange(t, changes, "b")
+	if val, _ := p.Lookup("TOKEN"); val != "b" {
+		t.Fatalf("expected the rotated value, got %q", val)
+	}
+}
+
+func TestWatchPolling(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "ENV_TOKEN")
+	rotate(t, path, "a")
+	p := NewSecretsProvider(dir)
+	changes := watchKey(p, "TOKEN")
+	stop := make(chan struct{})
+	defer close(stop)
+	go p.poll(10*time.Millisecond, stop)
+
+	rotate(t, path, "b")
+	waitChange(t, changes, "b")
+}
+
+func TestWatchFallsBackAfterDelete(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "secrets")
+	if err := os.Mkdir(dir, 0o700); err != nil {
+		t.Fatal(err)
+	}
+	rotate(t, filepath.Join(dir, "ENV_TOKEN"), "a")
+	p := NewSecretsProvider(dir)
+	changes := watchKey(p, "TOKEN")
+	if err := p.Watch(10 * time.Millisecond); err != nil {
+		t.Fatal(err)
+	}
+	defer p.Close()
+
+	// Recreating the directory ends an inotify watch, polling has to take over.
+	if err := os.RemoveAll(dir); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(50 * time.Millisecond)
+	if val, _ := p.Lookup("TOKEN"); val != "a" {
+		t.Fatalf("expected the previous value while the directory is missing, got %q", val)
+	}
+	if err := os.Mkdir(dir, 0o700); err != nil {
+		t.Fatal(err)
+	}
+	rotate(t, filepath.Join(dir, "ENV_TOKEN"), "b")
+	waitChange(t, changes, "b")
+}
+
+func TestWatchKubernetesSwap(t *testing.T) {
+	dir := t.TempDir()
+	// Creates a timestamped directory with the secret and points ..data to it.
+	publish := func(version, value string) {
+		if err := os.Mkdir(filepath.Join(dir, version), 0o700); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, version, "db-password"), []byte(value), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Symlink(version, filepath.Join(dir, "..data_tmp")); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Rename(filepath.Join(dir, "..data_tmp"), filepath.Join(dir, "..data")); err != nil {
+			t.Fatal(err)
+		}
+	}
+	publish("..2024_01_01", "a")
+	if err := os.Symlink(filepath.Join("..data", "db-password"), filepath.Join(dir, "db-password")); err != nil {
+		t.Fatal(err)
+	}
+	p := NewSecretsProvider(dir, KubernetesLayout())
+	if val, _ := p.Lookup("DB_PASSWORD"); val != "a" {
+		t.Fatalf("expected the initial value, got %q", val)
+	}
+	changes := watchKey(p, "DB_PASSWORD")
+	if err := p.Watch(time.Hour); err != nil {
+		t.Fatal(err)
+	}
+	defer p.Close()
+
+	publish("..2024_01_02", "b")
+	waitChange(t, changes, "b")
+	if _, ok := p.Lookup("..DATA"); ok {
+		t.Fatal("expected hidden entries to be skipped")
+	}
+}